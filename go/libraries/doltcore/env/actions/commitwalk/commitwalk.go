@@ -141,14 +141,18 @@ func newQueue() *q {
 // GetDotDotRevisions returns the commits reachable from commit at hash
 // `includedHead` that are not reachable from hash `excludedHead`.
 // `includedHead` and `excludedHead` must be commits in `ddb`. Returns up
-// to `num` commits, in reverse topological order starting at `includedHead`,
+// to `num` commits (if `num` <= 0 then all of them), in reverse topological
+// order starting at `includedHead`,
 // with tie breaking based on the height of commit graph between
 // concurrent commits --- higher commits appear first. Remaining
 // ties are broken by timestamp; newer commits appear first.
 //
 // Roughly mimics `git log master..feature`.
 func GetDotDotRevisions(ctx context.Context, includedDB *doltdb.DoltDB, includedHead hash.Hash, excludedDB *doltdb.DoltDB, excludedHead hash.Hash, num int) ([]*doltdb.Commit, error) {
-	commitList := make([]*doltdb.Commit, 0, num)
+	var commitList []*doltdb.Commit
+	if num > 0 {
+		commitList = make([]*doltdb.Commit, 0, num)
+	}
 	q := newQueue()
 	if err := q.SetInvisible(ctx, excludedDB, excludedHead); err != nil {
 		return nil, err
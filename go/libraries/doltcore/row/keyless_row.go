@@ -0,0 +1,263 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package row
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	keylessCardinalityValIdx = uint64(1)
+	keylessFirstValIdx       = uint64(2)
+)
+
+var ErrInvalidKeylessRow = errors.New("invalid keyless row")
+
+// keylessRow is a Row without primary key columns. The map key of a keyless row is derived from the hash of its
+// column values, and the map value stores the number of copies of the row (its cardinality) ahead of its fields.
+//
+//	key: Tuple(Uint(schema.KeylessRowIdTag), UUID(hash.Of(tag1, val1, ..., tagN, valN)))
+//	val: Tuple(Uint(schema.KeylessRowCardinalityTag), Uint(cardinality), Uint(tag1), Value(val1), ..., Uint(tagN), Value(valN))
+type keylessRow struct {
+	key types.Tuple
+	val types.Tuple
+}
+
+var _ Row = keylessRow{}
+
+// NewKeylessRow creates a Row for a keyless table from |colVals| which represents |card| identical copies of the row.
+// Null values are not stored.
+func NewKeylessRow(nbf *types.NomsBinFormat, sch schema.Schema, colVals TaggedValues, card uint64) (Row, error) {
+	allCols := sch.GetAllCols()
+
+	for tag := range colVals {
+		if _, ok := allCols.GetByTag(tag); !ok {
+			return nil, errors.New("Trying to set a value on an unknown tag is a bug.  Validation should happen upstream.")
+		}
+	}
+
+	vals := make([]types.Value, 0, len(colVals)*2)
+	for _, tag := range allCols.SortedTags {
+		val, ok := colVals[tag]
+		if !ok || types.IsNull(val) {
+			continue
+		}
+
+		vals = append(vals, types.Uint(tag), val)
+	}
+
+	return keylessRowWithCardinality(nbf, card, vals...)
+}
+
+// KeylessRowsFromTuples decodes a keyless row from its noms map entry, returning the row along with the number of
+// copies of the row stored in the map.
+func KeylessRowsFromTuples(key, val types.Tuple) (Row, uint64, error) {
+	card, err := KeylessCardinality(val)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return keylessRow{key: key, val: val}, card, nil
+}
+
+// KeylessCardinality returns the number of copies of a keyless row stored in its noms map value |val|. None of the
+// row's fields are decoded.
+func KeylessCardinality(val types.Tuple) (uint64, error) {
+	if val.Len() < keylessFirstValIdx {
+		return 0, ErrInvalidKeylessRow
+	}
+
+	c, err := val.Get(keylessCardinalityValIdx)
+	if err != nil {
+		return 0, err
+	}
+
+	card, ok := c.(types.Uint)
+	if !ok {
+		return 0, ErrInvalidKeylessRow
+	}
+
+	return uint64(card), nil
+}
+
+func keylessRowWithCardinality(nbf *types.NomsBinFormat, card uint64, vals ...types.Value) (Row, error) {
+	id, err := keylessRowId(nbf, vals...)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := types.NewTuple(nbf, types.Uint(schema.KeylessRowIdTag), id)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]types.Value, 0, len(vals)+2)
+	fields = append(fields, types.Uint(schema.KeylessRowCardinalityTag), types.Uint(card))
+	fields = append(fields, vals...)
+
+	val, err := types.NewTuple(nbf, fields...)
+	if err != nil {
+		return nil, err
+	}
+
+	return keylessRow{key: key, val: val}, nil
+}
+
+// keylessRowId hashes the tag, value pairs of a row. The cardinality is not part of the hash, so all copies of
+// a row share a single map key.
+func keylessRowId(nbf *types.NomsBinFormat, vals ...types.Value) (types.UUID, error) {
+	tup, err := types.NewTuple(nbf, vals...)
+	if err != nil {
+		return types.UUID{}, err
+	}
+
+	h, err := tup.Hash(nbf)
+	if err != nil {
+		return types.UUID{}, err
+	}
+
+	var id types.UUID
+	copy(id[:], h[:])
+
+	return id, nil
+}
+
+func (r keylessRow) NomsMapKey(sch schema.Schema) types.LesserValuable {
+	return r.key
+}
+
+func (r keylessRow) NomsMapValue(sch schema.Schema) types.Valuable {
+	return r.val
+}
+
+func (r keylessRow) IterCols(cb func(tag uint64, val types.Value) (stop bool, err error)) (bool, error) {
+	iter, err := r.val.IteratorAt(keylessFirstValIdx)
+	if err != nil {
+		return false, err
+	}
+
+	for iter.HasMore() {
+		_, t, err := iter.Next()
+		if err != nil {
+			return false, err
+		}
+
+		tag, ok := t.(types.Uint)
+		if !ok {
+			return false, fmt.Errorf("expected tag of type types.Uint, got %v", t)
+		}
+
+		_, val, err := iter.Next()
+		if err != nil {
+			return false, err
+		}
+
+		stop, err := cb(uint64(tag), val)
+		if err != nil {
+			return false, err
+		} else if stop {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r keylessRow) IterSchema(sch schema.Schema, cb func(tag uint64, val types.Value) (stop bool, err error)) (bool, error) {
+	tv, err := GetTaggedVals(r)
+	if err != nil {
+		return false, err
+	}
+
+	err = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (bool, error) {
+		return cb(tag, tv[tag])
+	})
+
+	return false, err
+}
+
+func (r keylessRow) GetColVal(tag uint64) (types.Value, bool) {
+	var val types.Value
+	_, _ = r.IterCols(func(t uint64, v types.Value) (stop bool, err error) {
+		if t == tag {
+			val = v
+			return true, nil
+		}
+		return false, nil
+	})
+
+	return val, val != nil
+}
+
+func (r keylessRow) SetColVal(tag uint64, val types.Value, sch schema.Schema) (Row, error) {
+	if _, ok := sch.GetAllCols().GetByTag(tag); !ok {
+		panic("can't set a column whose tag isn't in the schema.  verify before calling this function.")
+	}
+
+	tv, err := GetTaggedVals(r)
+	if err != nil {
+		return nil, err
+	}
+	tv[tag] = val
+
+	c, err := r.val.Get(keylessCardinalityValIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeylessRow(r.Format(), sch, tv, uint64(c.(types.Uint)))
+}
+
+func (r keylessRow) ReduceToIndex(idx schema.Index) (Row, error) {
+	tv, err := GetTaggedVals(r)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make(TaggedValues)
+	for _, tag := range idx.AllTags() {
+		if val, ok := tv[tag]; ok {
+			key[tag] = val
+		}
+	}
+
+	return nomsRow{key: key, value: make(TaggedValues), nbf: r.Format()}, nil
+}
+
+func (r keylessRow) ReduceToIndexPartialKey(idx schema.Index) (types.Tuple, error) {
+	tv, err := GetTaggedVals(r)
+	if err != nil {
+		return types.Tuple{}, err
+	}
+
+	var vals []types.Value
+	for _, tag := range idx.IndexedColumnTags() {
+		val, ok := tv[tag]
+		if !ok {
+			val = types.NullValue
+		}
+		vals = append(vals, types.Uint(tag), val)
+	}
+
+	return types.NewTuple(r.Format(), vals...)
+}
+
+func (r keylessRow) Format() *types.NomsBinFormat {
+	return r.key.Format()
+}
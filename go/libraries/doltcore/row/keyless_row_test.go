@@ -0,0 +1,105 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package row
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestKeylessRow(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	nbf := types.Format_Default
+
+	cc, err := schema.NewColCollection(testCols...)
+	require.NoError(t, err)
+	keylessSch, err := schema.SchemaFromCols(cc)
+	require.NoError(t, err)
+
+	vals := TaggedValues{
+		addrColTag:  addrVal,
+		ageColTag:   ageVal,
+		titleColTag: titleVal,
+	}
+
+	r, err := NewKeylessRow(nbf, keylessSch, vals, 3)
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		key, err := r.NomsMapKey(keylessSch).Value(ctx)
+		require.NoError(t, err)
+		val, err := r.NomsMapValue(keylessSch).Value(ctx)
+		require.NoError(t, err)
+
+		decoded, card, err := KeylessRowsFromTuples(key.(types.Tuple), val.(types.Tuple))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), card)
+		assert.True(t, AreEqual(r, decoded, keylessSch))
+
+		v, ok := decoded.GetColVal(addrColTag)
+		assert.True(t, ok)
+		assert.Equal(t, addrVal, v)
+
+		_, ok = decoded.GetColVal(titleColTag)
+		assert.False(t, ok)
+	})
+
+	t.Run("cardinality", func(t *testing.T) {
+		val, err := r.NomsMapValue(keylessSch).Value(ctx)
+		require.NoError(t, err)
+
+		card, err := KeylessCardinality(val.(types.Tuple))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), card)
+
+		_, err = KeylessCardinality(types.EmptyTuple(nbf))
+		assert.Equal(t, ErrInvalidKeylessRow, err)
+	})
+
+	t.Run("key ignores cardinality", func(t *testing.T) {
+		other, err := NewKeylessRow(nbf, keylessSch, vals, 1)
+		require.NoError(t, err)
+
+		key, err := r.NomsMapKey(keylessSch).Value(ctx)
+		require.NoError(t, err)
+		otherKey, err := other.NomsMapKey(keylessSch).Value(ctx)
+		require.NoError(t, err)
+		assert.True(t, key.Equals(otherKey))
+	})
+
+	t.Run("set col val", func(t *testing.T) {
+		updated, err := r.SetColVal(ageColTag, types.Uint(54), keylessSch)
+		require.NoError(t, err)
+
+		v, ok := updated.GetColVal(ageColTag)
+		assert.True(t, ok)
+		assert.Equal(t, types.Uint(54), v)
+
+		key, err := r.NomsMapKey(keylessSch).Value(ctx)
+		require.NoError(t, err)
+		updatedKey, err := updated.NomsMapKey(keylessSch).Value(ctx)
+		require.NoError(t, err)
+		assert.False(t, key.Equals(updatedKey))
+	})
+}
@@ -83,6 +83,75 @@ func DoltRowToSqlRow(doltRow Row, sch schema.Schema) (sql.Row, error) {
 	return sql.NewRow(colVals...), nil
 }
 
+// SqlRowConverter converts Dolt rows of a single schema to go-mysql-server sql.Rows. The column layout of the schema
+// is computed once when the converter is created, so converting many rows with the same converter avoids the per-row
+// column lookups done by DoltRowToSqlRow. Each row's values are visited once, which matters most for wide keyless
+// rows, whose individual column lookups each scan the row's fields.
+type SqlRowConverter struct {
+	tagToIdx map[uint64]int
+	cols     []schema.Column
+}
+
+// NewSqlRowConverter creates a SqlRowConverter for rows with the schema |sch|.
+func NewSqlRowConverter(sch schema.Schema) *SqlRowConverter {
+	allCols := sch.GetAllCols()
+
+	cols := make([]schema.Column, allCols.Size())
+	for i := range cols {
+		cols[i] = allCols.GetByIndex(i)
+	}
+
+	return &SqlRowConverter{tagToIdx: allCols.TagToIdx, cols: cols}
+}
+
+// Convert constructs a go-mysql-server sql.Row from |r|. Columns with no value in |r| are nil.
+func (conv *SqlRowConverter) Convert(r Row) (sql.Row, error) {
+	return conv.ConvertInto(r, nil)
+}
+
+// ConvertInto is like Convert, but writes the converted values into |dst| when it has room for every column, rather
+// than allocating a new sql.Row. The returned row is |dst| when it was reused.
+func (conv *SqlRowConverter) ConvertInto(r Row, dst sql.Row) (sql.Row, error) {
+	var colVals sql.Row
+	if cap(dst) >= len(conv.cols) {
+		colVals = dst[:len(conv.cols)]
+		for i := range colVals {
+			colVals[i] = nil
+		}
+	} else {
+		colVals = make(sql.Row, len(conv.cols))
+	}
+
+	_, err := r.IterCols(func(tag uint64, val types.Value) (stop bool, err error) {
+		idx, ok := conv.tagToIdx[tag]
+		if !ok {
+			return false, nil
+		}
+
+		colVals[idx], err = conv.cols[idx].TypeInfo.ConvertNomsValueToValue(val)
+		return err != nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return colVals, nil
+}
+
+// ConvertRows converts each of |rows| with Convert.
+func (conv *SqlRowConverter) ConvertRows(rows []Row) ([]sql.Row, error) {
+	sqlRows := make([]sql.Row, len(rows))
+	for i, r := range rows {
+		var err error
+		sqlRows[i], err = conv.Convert(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sqlRows, nil
+}
+
 // SqlRowToDoltRow constructs a Dolt row.Row from a go-mysql-server sql.Row.
 func SqlRowToDoltRow(nbf *types.NomsBinFormat, r sql.Row, doltSchema schema.Schema) (Row, error) {
 	taggedVals := make(TaggedValues)
@@ -100,5 +169,8 @@ func SqlRowToDoltRow(nbf *types.NomsBinFormat, r sql.Row, doltSchema schema.Sche
 			return nil, fmt.Errorf("column <%v> received nil but is non-nullable", schCol.Name)
 		}
 	}
+	if schema.IsKeyless(doltSchema) {
+		return NewKeylessRow(nbf, doltSchema, taggedVals, 1)
+	}
 	return New(nbf, doltSchema, taggedVals)
 }
@@ -0,0 +1,104 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package row
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestSqlRowConverter(t *testing.T) {
+	t.Run("keyed", func(t *testing.T) {
+		r, err := newTestRow()
+		require.NoError(t, err)
+
+		expected, err := DoltRowToSqlRow(r, sch)
+		require.NoError(t, err)
+
+		conv := NewSqlRowConverter(sch)
+		actual, err := conv.Convert(r)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+
+		rows, err := conv.ConvertRows([]Row{r, r})
+		require.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, expected, rows[1])
+	})
+
+	t.Run("keyless", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		keylessSch, err := schema.SchemaFromCols(testNonKeyColColl)
+		require.NoError(t, err)
+		r, err := NewKeylessRow(types.Format_7_18, keylessSch, TaggedValues{
+			addrColTag: addrVal,
+			ageColTag:  ageVal,
+		}, 2)
+		require.NoError(t, err)
+
+		expected, err := DoltRowToSqlRow(r, keylessSch)
+		require.NoError(t, err)
+
+		actual, err := NewSqlRowConverter(keylessSch).Convert(r)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+}
+
+// BenchmarkSqlRowConverter converts a wide keyless row. Run with -benchtime=1000000x to convert a million rows.
+func BenchmarkSqlRowConverter(b *testing.B) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	const numCols = 40
+
+	var cols []schema.Column
+	tv := make(TaggedValues, numCols)
+	for tag := uint64(0); tag < numCols; tag++ {
+		cols = append(cols, schema.NewColumn(fmt.Sprintf("c%d", tag), tag, types.IntKind, false))
+		tv[tag] = types.Int(tag)
+	}
+	coll, err := schema.NewColCollection(cols...)
+	require.NoError(b, err)
+	wideSch, err := schema.SchemaFromCols(coll)
+	require.NoError(b, err)
+	r, err := NewKeylessRow(types.Format_7_18, wideSch, tv, 1)
+	require.NoError(b, err)
+
+	b.Run("DoltRowToSqlRow", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := DoltRowToSqlRow(r, wideSch)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("SqlRowConverter", func(b *testing.B) {
+		b.ReportAllocs()
+		conv := NewSqlRowConverter(wideSch)
+		for i := 0; i < b.N; i++ {
+			_, err := conv.Convert(r)
+			require.NoError(b, err)
+		}
+	})
+}
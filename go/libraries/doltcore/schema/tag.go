@@ -29,6 +29,12 @@ import (
 const (
 	// ReservedTagMin is the start of a range of tags which the user should not be able to use in their schemas.
 	ReservedTagMin uint64 = 1 << 50
+
+	// KeylessRowIdTag is the tag of the row id field in the noms map key of a keyless row.
+	KeylessRowIdTag uint64 = InvalidTag - 1
+
+	// KeylessRowCardinalityTag is the tag of the cardinality field in the noms map value of a keyless row.
+	KeylessRowCardinalityTag uint64 = KeylessRowIdTag - 1
 )
 
 func ErrTagPrevUsed(tag uint64, newColName, tableName string) error {
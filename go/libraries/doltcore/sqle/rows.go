@@ -30,13 +30,13 @@ type doltTableRowIter struct {
 }
 
 // Returns a new row iterator for the table given
-func newRowIterator(tbl *DoltTable, ctx *sql.Context, partition *doltTablePartition) (*doltTableRowIter, error) {
+func newRowIterator(tbl *DoltTable, ctx *sql.Context, partition *table.TablePartition) (*doltTableRowIter, error) {
 	var iter table.SqlTableReader
 	var err error
 	if partition == nil {
 		iter, err = table.NewBufferedTableReader(ctx, tbl.table)
 	} else {
-		iter, err = table.NewBufferedTableReaderForPartition(ctx, tbl.table, partition.start, partition.end)
+		iter, err = partition.Reader(ctx)
 	}
 
 	if err != nil {
@@ -54,7 +54,11 @@ func (itr *doltTableRowIter) Next() (sql.Row, error) {
 	return itr.reader.ReadSqlRow(itr.ctx)
 }
 
-// Close required by sql.RowIter interface
+// Close required by sql.RowIter interface. Closes the underlying reader, including any readers it wraps.
 func (itr *doltTableRowIter) Close() error {
+	if closer, ok := itr.reader.(table.TableCloser); ok {
+		return closer.Close(itr.ctx)
+	}
+
 	return nil
 }
@@ -0,0 +1,47 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dolthub/dolt/go/libraries/doltcore/sql/sqltestutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+)
+
+type closeRecordingReader struct {
+	table.SqlTableReader
+	closed bool
+}
+
+func (rd *closeRecordingReader) Close(ctx context.Context) error {
+	rd.closed = true
+	return nil
+}
+
+func TestDoltTableRowIterClose(t *testing.T) {
+	// readers that take a context to Close, as every reader in the table package does, are closed by the iterator
+	rd := &closeRecordingReader{SqlTableReader: table.NewInMemTableReader(table.NewInMemTableWithData(PeopleTestSchema, AllPeopleRows))}
+	itr := &doltTableRowIter{ctx: context.Background(), reader: rd}
+
+	_, err := itr.Next()
+	require.NoError(t, err)
+	require.NoError(t, itr.Close())
+	assert.True(t, rd.closed)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	. "github.com/dolthub/dolt/go/libraries/doltcore/sql/sqltestutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
@@ -50,12 +51,15 @@ type UpdateTest struct {
 	ExpectedSchema schema.Schema
 	// The rows this query should return, nil if an error is expected
 	ExpectedRows []sql.Row
-	// An expected error string
+	// A substring of the expected error, empty if no error is expected
 	ExpectedErr string
 	// Setup logic to run before executing this test, after initial tables have been created and populated
 	AdditionalSetup SetupFn
 }
 
+// binaryTestSchema is the schema of a table with a binary column, for testing updates with binary values
+var binaryTestSchema = NewSchema("id", types.IntKind, "data", types.InlineBlobKind)
+
 // BasicUpdateTests cover basic update statement features and error handling
 var BasicUpdateTests = []UpdateTest{
 	{
@@ -185,6 +189,210 @@ var BasicUpdateTests = []UpdateTest{
 		),
 		ExpectedSchema: CompressSchema(PeopleTestSchema),
 	},
+	{
+		Name:        "update increment rating",
+		UpdateQuery: `update people set rating = rating + 1 where id = 0`,
+		SelectQuery: `select * from people where id = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, RatingTag, 9.5),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update float col from int col expression",
+		UpdateQuery: `update people set rating = age + 0.5 where last_name = "Simpson"`,
+		SelectQuery: `select * from people where last_name = "Simpson"`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, RatingTag, 40.5),
+			MutateRow(PeopleTestSchema, Marge, RatingTag, 38.5),
+			MutateRow(PeopleTestSchema, Bart, RatingTag, 10.5),
+			MutateRow(PeopleTestSchema, Lisa, RatingTag, 8.5),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update uint col from uint and float expression",
+		UpdateQuery: `update people set num_episodes = num_episodes * 1.5 where id = 2`,
+		SelectQuery: `select * from people where id = 2`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Bart, NumEpisodesTag, uint64(333)),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update int col from fractional expression",
+		UpdateQuery: `update people set age = age / 3 where id = 0`,
+		SelectQuery: `select * from people where id = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, AgeTag, 13),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update uint col out of range",
+		UpdateQuery: `update people set num_episodes = num_episodes - 1000 where id = 1`,
+		ExpectedErr: "out of range",
+	},
+	{
+		Name:        "update col from primary key expression",
+		UpdateQuery: `update people set age = id * 1000 where id < 2`,
+		SelectQuery: `select * from people where id < 2`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, AgeTag, 0),
+			MutateRow(PeopleTestSchema, Marge, AgeTag, 1000),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update col from composite primary key expression",
+		UpdateQuery: `update appearances set comments = concat(character_id, '-', episode_id) where episode_id = 1`,
+		SelectQuery: `select comments from appearances where episode_id = 1 order by character_id`,
+		ExpectedRows: ToSqlRows(NewResultSetSchema("comments", types.StringKind),
+			NewResultSetRow(types.String("0-1")),
+			NewResultSetRow(types.String("1-1")),
+		),
+		ExpectedSchema: NewResultSetSchema("comments", types.StringKind),
+	},
+	{
+		Name:        "update int col with bitwise or",
+		UpdateQuery: `update people set age = age | 4 where id = 0`,
+		SelectQuery: `select * from people where id = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, AgeTag, 44),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update int col with bitwise and",
+		UpdateQuery: `update people set age = age & 8 where id = 0`,
+		SelectQuery: `select * from people where id = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, AgeTag, 8),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update int col with bitwise xor",
+		UpdateQuery: `update people set age = age ^ 1 where id = 0`,
+		SelectQuery: `select * from people where id = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, AgeTag, 41),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update int cols with shifts",
+		UpdateQuery: `update people set age = age << 1, num_episodes = num_episodes >> 2 where id = 1`,
+		SelectQuery: `select * from people where id = 1`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Marge, AgeTag, 76, NumEpisodesTag, uint64(27)),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update uint col with left shift wraparound",
+		UpdateQuery: `update people set num_episodes = num_episodes << 63 where id = 1`,
+		SelectQuery: `select * from people where id = 1`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Marge, NumEpisodesTag, uint64(1)<<63),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update string col with concat of col",
+		UpdateQuery: `update people set first_name = concat(first_name, "_old") where id = 0`,
+		SelectQuery: `select * from people where id = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, FirstNameTag, "Homer_old"),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update string col with upper and trim",
+		UpdateQuery: `update people set first_name = upper(trim(concat("  ", first_name, "  "))) where id = 1`,
+		SelectQuery: `select * from people where id = 1`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Marge, FirstNameTag, "MARGE"),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update string col with lower and substring",
+		UpdateQuery: `update people set first_name = lower(substring(first_name, 1, 2)) where id = 2`,
+		SelectQuery: `select * from people where id = 2`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Bart, FirstNameTag, "ba"),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update string col with replace",
+		UpdateQuery: `update people set last_name = replace(last_name, "Simp", "Samp") where id = 3`,
+		SelectQuery: `select * from people where id = 3`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Lisa, LastNameTag, "Sampson"),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update string col with concat of null",
+		UpdateQuery: `update people set first_name = coalesce(concat(first_name, null), "was null") where id = 4`,
+		SelectQuery: `select * from people where id = 4`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Moe, FirstNameTag, "was null"),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update string col with cast of int",
+		UpdateQuery: `update people set first_name = cast(age as char) where id = 0`,
+		SelectQuery: `select * from people where id = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, FirstNameTag, "40"),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update int col with cast of numeric string",
+		UpdateQuery: `update people set age = cast("52" as signed) where id = 1`,
+		SelectQuery: `select * from people where id = 1`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Marge, AgeTag, 52),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update int col with cast of fraction rounds half up",
+		UpdateQuery: `update people set age = cast(2.5 as signed) where id = 0`,
+		SelectQuery: `select * from people where id = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, AgeTag, 3),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update int col with cast of negative fraction rounds away from zero",
+		UpdateQuery: `update people set age = cast(-2.5 as signed) where id = 1`,
+		SelectQuery: `select * from people where id = 1`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Marge, AgeTag, -3),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update int col with cast of fraction below one half",
+		UpdateQuery: `update people set age = cast(2.4 as signed) where id = 2`,
+		SelectQuery: `select * from people where id = 2`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Bart, AgeTag, 2),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update expression with nonexistent column",
+		UpdateQuery: `update people set rating = not_a_column + 1 where id = 0`,
+		ExpectedErr: "column \"not_a_column\" could not be found",
+	},
 	{
 		Name:        "update datetime field",
 		UpdateQuery: `update episodes set air_date = "1993-03-24 20:00:00" where id = 1`,
@@ -203,6 +411,47 @@ var BasicUpdateTests = []UpdateTest{
 		),
 		ExpectedSchema: CompressSchema(EpisodesTestSchema),
 	},
+	{
+		Name:        "update datetime field, add interval",
+		UpdateQuery: `update episodes set air_date = air_date + interval 1 day where id = 1`,
+		SelectQuery: `select * from episodes where id = 1`,
+		ExpectedRows: ToSqlRows(EpisodesTestSchema,
+			MutateRow(EpisodesTestSchema, Ep1, EpAirDateTag, DatetimeStrToTimestamp("1989-12-19 03:00:00")),
+		),
+		ExpectedSchema: CompressSchema(EpisodesTestSchema),
+	},
+	{
+		Name:        "update datetime field, subtract interval",
+		UpdateQuery: `update episodes set air_date = air_date - interval 4 hour where id = 1`,
+		SelectQuery: `select * from episodes where id = 1`,
+		ExpectedRows: ToSqlRows(EpisodesTestSchema,
+			MutateRow(EpisodesTestSchema, Ep1, EpAirDateTag, DatetimeStrToTimestamp("1989-12-17 23:00:00")),
+		),
+		ExpectedSchema: CompressSchema(EpisodesTestSchema),
+	},
+	{
+		Name:        "update datetime field, add negative interval",
+		UpdateQuery: `update episodes set air_date = air_date + interval -30 minute where id = 2`,
+		SelectQuery: `select * from episodes where id = 2`,
+		ExpectedRows: ToSqlRows(EpisodesTestSchema,
+			MutateRow(EpisodesTestSchema, Ep2, EpAirDateTag, DatetimeStrToTimestamp("1990-01-15 02:30:00")),
+		),
+		ExpectedSchema: CompressSchema(EpisodesTestSchema),
+	},
+	{
+		Name:        "update datetime field, add month interval",
+		UpdateQuery: `update episodes set air_date = air_date + interval 1 month where id = 3`,
+		SelectQuery: `select * from episodes where id = 3`,
+		ExpectedRows: ToSqlRows(EpisodesTestSchema,
+			MutateRow(EpisodesTestSchema, Ep3, EpAirDateTag, DatetimeStrToTimestamp("1990-02-22 03:00:00")),
+		),
+		ExpectedSchema: CompressSchema(EpisodesTestSchema),
+	},
+	{
+		Name:        "update non-datetime field with interval",
+		UpdateQuery: `update episodes set name = name + interval 1 day where id = 1`,
+		ExpectedErr: "can't be converted to time.Time",
+	},
 	{
 		Name:        "update multiple rows, =",
 		UpdateQuery: `update people set first_name = "Homer" where last_name = "Simpson"`,
@@ -278,6 +527,385 @@ var BasicUpdateTests = []UpdateTest{
 		),
 		ExpectedSchema: CompressSchema(PeopleTestSchema),
 	},
+	{
+		Name:        "update multiple rows, in",
+		UpdateQuery: `update people set rating = 0 where id in (0, 1, 2)`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Marge, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Bart, RatingTag, 0.0),
+			Lisa,
+			Moe,
+			Barney,
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, in string list",
+		UpdateQuery: `update people set rating = 0 where first_name in ("Lisa", "Moe", "Ned")`,
+		SelectQuery: `select * from people where first_name in ("Lisa", "Moe")`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Lisa, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:           "update no rows, in with no matches",
+		UpdateQuery:    `update people set rating = 0 where id in (10, 11, 12)`,
+		SelectQuery:    `select * from people`,
+		ExpectedRows:   ToSqlRows(PeopleTestSchema, AllPeopleRows...),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, not in",
+		UpdateQuery: `update people set rating = 0 where id not in (0, 1, 2, 3)`,
+		SelectQuery: `select * from people where id > 3`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Barney, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update with empty in list",
+		UpdateQuery: `update people set rating = 0 where id in ()`,
+		ExpectedErr: "syntax error",
+	},
+	{
+		Name:        "update multiple rows, between",
+		UpdateQuery: `update people set first_name = "Homer" where age between 10 and 40`,
+		SelectQuery: `select * from people where age between 10 and 40`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			Homer,
+			MutateRow(PeopleTestSchema, Marge, FirstNameTag, "Homer"),
+			MutateRow(PeopleTestSchema, Bart, FirstNameTag, "Homer"),
+			MutateRow(PeopleTestSchema, Barney, FirstNameTag, "Homer"),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, not between",
+		UpdateQuery: `update people set first_name = "Homer" where age not between 10 and 40`,
+		SelectQuery: `select * from people where age < 10 or age > 40`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Lisa, FirstNameTag, "Homer"),
+			MutateRow(PeopleTestSchema, Moe, FirstNameTag, "Homer"),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:           "update no rows, between with reversed bounds",
+		UpdateQuery:    `update people set first_name = "Homer" where age between 40 and 10`,
+		SelectQuery:    `select * from people`,
+		ExpectedRows:   ToSqlRows(PeopleTestSchema, AllPeopleRows...),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, between skips null values",
+		UpdateQuery: `update people set rating = 0 where num_episodes between 0 and 1000`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			Homer,
+			MutateRow(PeopleTestSchema, Marge, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Bart, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Lisa, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Barney, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, and",
+		UpdateQuery: `update people set first_name = "Homer" where last_name = "Simpson" and age > 10`,
+		SelectQuery: `select * from people where last_name = "Simpson"`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			Homer,
+			MutateRow(PeopleTestSchema, Marge, FirstNameTag, "Homer"),
+			Bart,
+			Lisa,
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, or",
+		UpdateQuery: `update people set rating = 0 where first_name = "Moe" or age < 10`,
+		SelectQuery: `select * from people where rating = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Lisa, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, parenthesized or with and not",
+		UpdateQuery: `update people set rating = 0 where (last_name = "Simpson" or last_name = "Gumble") and not is_married`,
+		SelectQuery: `select * from people where rating = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Bart, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Lisa, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Barney, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, or with null operands",
+		UpdateQuery: `update people set rating = 0 where num_episodes > 300 or not (num_episodes > 300)`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			Homer,
+			MutateRow(PeopleTestSchema, Marge, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Bart, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Lisa, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Barney, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, like prefix",
+		UpdateQuery: `update people set rating = 0 where last_name like "Sim%"`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Marge, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Bart, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Lisa, RatingTag, 0.0),
+			Moe,
+			Barney,
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, like suffix",
+		UpdateQuery: `update people set rating = 0 where first_name like "%e"`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			Homer,
+			MutateRow(PeopleTestSchema, Marge, RatingTag, 0.0),
+			Bart,
+			Lisa,
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 0.0),
+			Barney,
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, like middle wildcard",
+		UpdateQuery: `update people set rating = 0 where first_name like "%ar%"`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			Homer,
+			MutateRow(PeopleTestSchema, Marge, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Bart, RatingTag, 0.0),
+			Lisa,
+			Moe,
+			MutateRow(PeopleTestSchema, Barney, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, like single character wildcard",
+		UpdateQuery: `update people set rating = 0 where first_name like "_o%"`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, RatingTag, 0.0),
+			Marge,
+			Bart,
+			Lisa,
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 0.0),
+			Barney,
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, not like",
+		UpdateQuery: `update people set rating = 0 where last_name not like "Sim%"`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			Homer,
+			Marge,
+			Bart,
+			Lisa,
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Barney, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:           "update no rows, like escaped wildcard",
+		UpdateQuery:    `update people set rating = 0 where first_name like "%\\_%"`,
+		SelectQuery:    `select * from people`,
+		ExpectedRows:   ToSqlRows(PeopleTestSchema, AllPeopleRows...),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, in subquery",
+		UpdateQuery: `update people set rating = 0 where id in (select character_id from appearances where episode_id = 2)`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, RatingTag, 0.0),
+			Marge,
+			MutateRow(PeopleTestSchema, Bart, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Lisa, RatingTag, 0.0),
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 0.0),
+			Barney,
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, not in subquery",
+		UpdateQuery: `update people set rating = 0 where id not in (select character_id from appearances where episode_id = 2)`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			Homer,
+			MutateRow(PeopleTestSchema, Marge, RatingTag, 0.0),
+			Bart,
+			Lisa,
+			Moe,
+			MutateRow(PeopleTestSchema, Barney, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update null-safe equals null",
+		UpdateQuery: `update people set rating = 0 where num_episodes <=> null`,
+		SelectQuery: `select * from people where rating = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:           "update no rows, equals null",
+		UpdateQuery:    `update people set rating = 0 where num_episodes = null`,
+		SelectQuery:    `select * from people`,
+		ExpectedRows:   ToSqlRows(PeopleTestSchema, AllPeopleRows...),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:           "update no rows, string equals differs in case",
+		UpdateQuery:    `update people set rating = 0 where last_name = "simpson"`,
+		SelectQuery:    `select * from people`,
+		ExpectedRows:   ToSqlRows(PeopleTestSchema, AllPeopleRows...),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update null-safe equals value",
+		UpdateQuery: `update people set rating = 0 where num_episodes <=> 111`,
+		SelectQuery: `select * from people where rating = 0`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Marge, RatingTag, 0.0),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, case with else",
+		UpdateQuery: `update people set rating = case when age > 40 then 10 when age >= 38 then 7.5 else 5 end`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, RatingTag, 7.5),
+			MutateRow(PeopleTestSchema, Marge, RatingTag, 7.5),
+			MutateRow(PeopleTestSchema, Bart, RatingTag, 5.0),
+			MutateRow(PeopleTestSchema, Lisa, RatingTag, 5.0),
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 10.0),
+			MutateRow(PeopleTestSchema, Barney, RatingTag, 7.5),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, case with no match and no else",
+		UpdateQuery: `update people set rating = case when age > 40 then 10 end`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, RatingTag, nil),
+			MutateRow(PeopleTestSchema, Marge, RatingTag, nil),
+			MutateRow(PeopleTestSchema, Bart, RatingTag, nil),
+			MutateRow(PeopleTestSchema, Lisa, RatingTag, nil),
+			MutateRow(PeopleTestSchema, Moe, RatingTag, 10.0),
+			MutateRow(PeopleTestSchema, Barney, RatingTag, nil),
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update multiple rows, case on value",
+		UpdateQuery: `update people set first_name = case last_name when "Simpson" then "Bart" when "Szyslak" then "Moe" else "Barney" end`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: ToSqlRows(PeopleTestSchema,
+			MutateRow(PeopleTestSchema, Homer, FirstNameTag, "Bart"),
+			MutateRow(PeopleTestSchema, Marge, FirstNameTag, "Bart"),
+			Bart,
+			MutateRow(PeopleTestSchema, Lisa, FirstNameTag, "Bart"),
+			Moe,
+			Barney,
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name: "update binary col with hex string literal",
+		AdditionalSetup: CreateTableWithRowsFn("binary_test", binaryTestSchema,
+			[]types.Value{types.Int(1), types.InlineBlob("abc")}),
+		UpdateQuery:    `update binary_test set data = x'deadbeef' where id = 1`,
+		SelectQuery:    `select * from binary_test`,
+		ExpectedRows:   []sql.Row{{int64(1), "\xde\xad\xbe\xef"}},
+		ExpectedSchema: CompressSchema(binaryTestSchema),
+	},
+	{
+		Name: "update binary col with hex number literal",
+		AdditionalSetup: CreateTableWithRowsFn("binary_test", binaryTestSchema,
+			[]types.Value{types.Int(1), types.InlineBlob("abc")}),
+		UpdateQuery:    `update binary_test set data = 0xDEADBEEF where id = 1`,
+		SelectQuery:    `select * from binary_test`,
+		ExpectedRows:   []sql.Row{{int64(1), "\xde\xad\xbe\xef"}},
+		ExpectedSchema: CompressSchema(binaryTestSchema),
+	},
+	{
+		// MySQL treats hex literals as binary strings, which may be assigned to character columns
+		Name:           "update string col with hex string literal",
+		UpdateQuery:    `update people set first_name = x'446f6d6572' where id = 0`,
+		SelectQuery:    `select * from people where id = 0`,
+		ExpectedRows:   ToSqlRows(PeopleTestSchema, MutateRow(PeopleTestSchema, Homer, FirstNameTag, "Domer")),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:           "update with table alias",
+		UpdateQuery:    `update people p set p.first_name = "Domer" where p.id = 0`,
+		SelectQuery:    `select * from people where id = 0`,
+		ExpectedRows:   ToSqlRows(PeopleTestSchema, MutateRow(PeopleTestSchema, Homer, FirstNameTag, "Domer")),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:           "update with table name qualifier",
+		UpdateQuery:    `update people set people.first_name = "Domer" where people.id = 0`,
+		SelectQuery:    `select * from people where id = 0`,
+		ExpectedRows:   ToSqlRows(PeopleTestSchema, MutateRow(PeopleTestSchema, Homer, FirstNameTag, "Domer")),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update with qualifier not matching table alias",
+		UpdateQuery: `update people p set q.first_name = "Domer" where p.id = 0`,
+		ExpectedErr: "table not found: q",
+	},
+	{
+		Name:        "update with table name qualifier when table is aliased",
+		UpdateQuery: `update people p set people.first_name = "Domer" where p.id = 0`,
+		ExpectedErr: "table not found: people",
+	},
+	{
+		Name:           "update bool col from int 0",
+		UpdateQuery:    `update people set is_married = 0 where id = 0`,
+		SelectQuery:    `select * from people where id = 0`,
+		ExpectedRows:   ToSqlRows(PeopleTestSchema, MutateRow(PeopleTestSchema, Homer, IsMarriedTag, false)),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:           "update bool col from int 1",
+		UpdateQuery:    `update people set is_married = 1 where id = 3`,
+		SelectQuery:    `select * from people where id = 3`,
+		ExpectedRows:   ToSqlRows(PeopleTestSchema, MutateRow(PeopleTestSchema, Lisa, IsMarriedTag, true)),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
 	{
 		Name:        "update multiple rows pk increment order by desc",
 		UpdateQuery: `update people set id = id + 1 order by id desc`,
@@ -314,42 +942,42 @@ var BasicUpdateTests = []UpdateTest{
 	{
 		Name:        "null constraint failure",
 		UpdateQuery: `update people set first_name = null where id = 0`,
-		ExpectedErr: "Constraint failed for column 'first_name': Not null",
+		ExpectedErr: "column <first_name> received nil but is non-nullable",
 	},
 	{
 		Name:        "type mismatch list -> string",
 		UpdateQuery: `update people set first_name = ("one", "two") where id = 0`,
-		ExpectedErr: "Type mismatch",
+		ExpectedErr: "operand should have 1 columns",
 	},
 	{
 		Name:        "type mismatch int -> uuid",
 		UpdateQuery: `update people set uuid = 0 where id = 0`,
-		ExpectedErr: "Type mismatch",
+		ExpectedErr: "invalid",
 	},
 	{
 		Name:        "type mismatch string -> int",
 		UpdateQuery: `update people set age = "pretty old" where id = 0`,
-		ExpectedErr: "Type mismatch",
+		ExpectedErr: "pretty old",
 	},
 	{
 		Name:        "type mismatch string -> float",
 		UpdateQuery: `update people set rating = "great" where id = 0`,
-		ExpectedErr: "Type mismatch",
+		ExpectedErr: "great",
 	},
 	{
 		Name:        "type mismatch string -> uint",
 		UpdateQuery: `update people set num_episodes = "all of them" where id = 0`,
-		ExpectedErr: "Type mismatch",
+		ExpectedErr: "all of them",
 	},
 	{
 		Name:        "type mismatch string -> uuid",
 		UpdateQuery: `update people set uuid = "not a uuid string" where id = 0`,
-		ExpectedErr: "Type mismatch",
+		ExpectedErr: "invalid UUID",
 	},
 	{
 		Name:        "type mismatch bool -> uuid",
 		UpdateQuery: `update people set uuid = false where id = 0`,
-		ExpectedErr: "Type mismatch",
+		ExpectedErr: "invalid",
 	},
 }
 
@@ -375,8 +1003,18 @@ var systemTableUpdateTests = []UpdateTest{
 		AdditionalSetup: CreateTableFn("dolt_docs",
 			env.DoltDocsSchema,
 			NewRow(types.String("LICENSE.md"), types.String("A license"))),
-		UpdateQuery: "update dolt_docs set doc_text = 'Some text')",
-		ExpectedErr: "cannot insert into table",
+		UpdateQuery: "update dolt_docs set doc_text = 'Some text'",
+		ExpectedErr: "table doesn't support UPDATE",
+	},
+	{
+		Name:        "update dolt_log",
+		UpdateQuery: "update dolt_log set message = 'rewritten history'",
+		ExpectedErr: "table doesn't support UPDATE",
+	},
+	{
+		Name:        "update dolt_diff table",
+		UpdateQuery: "update dolt_diff_people set to_first_name = 'Changed'",
+		ExpectedErr: "table doesn't support UPDATE",
 	},
 	{
 		Name: "update dolt_query_catalog",
@@ -437,6 +1075,7 @@ func testUpdateQuery(t *testing.T, test UpdateTest) {
 	root, err = executeModify(context.Background(), dEnv, root, test.UpdateQuery)
 	if len(test.ExpectedErr) > 0 {
 		require.Error(t, err)
+		assert.Contains(t, err.Error(), test.ExpectedErr)
 		return
 	} else {
 		require.NoError(t, err)
@@ -449,3 +1088,152 @@ func testUpdateQuery(t *testing.T, test UpdateTest) {
 	sqlSchema := mustSqlSchema(test.ExpectedSchema)
 	assertSchemasEqual(t, sqlSchema, sch)
 }
+
+func TestUpdateOnBranch(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+
+	head, err := dEnv.DoltDB.ResolveRef(ctx, dEnv.RepoState.CWBHeadRef())
+	require.NoError(t, err)
+	err = dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("other"), head)
+	require.NoError(t, err)
+
+	root, err := ExecuteSqlOnBranch(dEnv, "other", `create table test (a int primary key, b int);
+insert into test values (1, 1), (2, 2);
+update test set b = 0 where a = 1`)
+	require.NoError(t, err)
+
+	rows, _, err := executeSelect(ctx, dEnv, root, "select * from test")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(1), int64(0)}, {int64(2), int64(2)}}, rows)
+
+	// neither the checked out branch nor the updated branch are changed
+	working, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	has, err := working.HasTable(ctx, "test")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	other, err := dEnv.DoltDB.ResolveRef(ctx, ref.NewBranchRef("other"))
+	require.NoError(t, err)
+	otherRoot, err := other.GetRootValue()
+	require.NoError(t, err)
+	has, err = otherRoot.HasTable(ctx, "test")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	_, err = ExecuteSqlOnBranch(dEnv, "not_a_branch", "update test set b = 0")
+	assert.Equal(t, doltdb.ErrBranchNotFound, err)
+}
+
+func TestUpdateOnCheckedOutBranch(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+
+	// the table only exists in the working root, not in the branch's head commit
+	working, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	working, err = ExecuteSql(dEnv, working, `create table test (a int primary key, b int);
+insert into test values (1, 1), (2, 2)`)
+	require.NoError(t, err)
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, working))
+
+	root, err := ExecuteSqlOnBranch(dEnv, dEnv.RepoState.CWBHeadRef().GetPath(), "update test set b = 0 where a = 1")
+	require.NoError(t, err)
+
+	rows, _, err := executeSelect(ctx, dEnv, root, "select * from test")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(1), int64(0)}, {int64(2), int64(2)}}, rows)
+
+	// the working set is not changed
+	working, err = dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	rows, _, err = executeSelect(ctx, dEnv, working, "select * from test")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(1), int64(1)}, {int64(2), int64(2)}}, rows)
+}
+
+func TestUpdateStatementsAreAtomic(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	// the first update succeeds on its own
+	updated, err := ExecuteSql(dEnv, root, `update people set first_name = "Changed" where id = 0`)
+	require.NoError(t, err)
+	rows, _, err := executeSelect(ctx, dEnv, updated, "select first_name from people where id = 0")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"Changed"}}, rows)
+
+	// the second update fails, so neither update is applied
+	updated, err = ExecuteSql(dEnv, root, `update people set first_name = "Changed" where id = 0;
+update people set id = 1 where id = 0`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate primary key")
+	assert.Nil(t, updated)
+
+	rows, _, err = executeSelect(ctx, dEnv, root, "select * from people")
+	require.NoError(t, err)
+	assert.Equal(t, ToSqlRows(PeopleTestSchema, AllPeopleRows...), rows)
+
+	working, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	rows, _, err = executeSelect(ctx, dEnv, working, "select * from people")
+	require.NoError(t, err)
+	assert.Equal(t, ToSqlRows(PeopleTestSchema, AllPeopleRows...), rows)
+}
+
+func TestUpdateNow(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	for _, fn := range []string{"now()", "current_timestamp()", "current_timestamp"} {
+		t.Run(fn, func(t *testing.T) {
+			updated, err := ExecuteSql(dEnv, root, "update episodes set air_date = "+fn)
+			require.NoError(t, err)
+
+			// NOW() is evaluated once per statement, so every row gets the same value
+			rows, _, err := executeSelect(ctx, dEnv, updated, "select count(*), count(distinct air_date) from episodes")
+			require.NoError(t, err)
+			assert.Equal(t, []sql.Row{{int64(len(AllEpsRows)), int64(1)}}, rows)
+
+			rows, _, err = executeSelect(ctx, dEnv, updated, "select count(*) from episodes where air_date > '2020-01-01'")
+			require.NoError(t, err)
+			assert.Equal(t, []sql.Row{{int64(len(AllEpsRows))}}, rows)
+		})
+	}
+}
+
+func TestUpdateAutoIncrementKey(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `create table auto (pk int primary key auto_increment, c int);
+insert into auto (c) values (1), (2);
+update auto set pk = 10 where pk = 2;
+insert into auto (c) values (3);
+update auto set pk = 5 where pk = 1;
+insert into auto (c) values (4)`)
+	require.NoError(t, err)
+
+	// moving a key past the counter advances it, but moving a key below the counter doesn't move it back
+	rows, _, err := executeSelect(ctx, dEnv, root, "select * from auto order by pk")
+	require.NoError(t, err)
+	expected := []sql.Row{
+		{int64(5), int64(1)},
+		{int64(10), int64(2)},
+		{int64(11), int64(3)},
+		{int64(12), int64(4)},
+	}
+	assert.Equal(t, expected, rows)
+}
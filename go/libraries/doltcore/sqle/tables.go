@@ -17,12 +17,9 @@ package sqle
 import (
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"runtime"
-	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/vitess/go/sqltypes"
@@ -216,31 +213,29 @@ func (t *DoltTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
 
 	numElements := rowData.Len()
 
-	if numElements == 0 {
-		return newDoltTablePartitionIter(rowData, doltTablePartition{0, 1}), nil
-	}
-
-	maxPartitions := uint64(partitionMultiplier * runtime.NumCPU())
-	numPartitions := (numElements / MinRowsPerPartition) + 1
+	// an empty table is read as a single empty partition
+	numPartitions := uint64(1)
+	if numElements > 0 {
+		maxPartitions := uint64(partitionMultiplier * runtime.NumCPU())
+		numPartitions = (numElements / MinRowsPerPartition) + 1
 
-	if numPartitions > maxPartitions {
-		numPartitions = maxPartitions
+		if numPartitions > maxPartitions {
+			numPartitions = maxPartitions
+		}
 	}
 
-	partitions := make([]doltTablePartition, numPartitions)
-	itemsPerPartition := numElements / numPartitions
-	for i := uint64(0); i < numPartitions-1; i++ {
-		partitions[i] = doltTablePartition{i * itemsPerPartition, (i + 1) * itemsPerPartition}
+	partitions, err := table.NewPartitionIter(ctx, t.table, numPartitions)
+	if err != nil {
+		return nil, err
 	}
-	partitions[numPartitions-1] = doltTablePartition{(numPartitions - 1) * itemsPerPartition, numElements}
 
-	return newDoltTablePartitionIter(rowData, partitions...), nil
+	return partitions, nil
 }
 
 // Returns the table rows for the partition given
 func (t *DoltTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sql.RowIter, error) {
 	switch typedPartition := partition.(type) {
-	case doltTablePartition:
+	case table.TablePartition:
 		return newRowIterator(t, ctx, &typedPartition)
 	case sqlutil.SinglePartition:
 		return newRowIterator(t, ctx, nil)
@@ -389,54 +384,6 @@ func (t *DoltTable) GetForeignKeys(ctx *sql.Context) ([]sql.ForeignKeyConstraint
 	return toReturn, nil
 }
 
-var _ sql.PartitionIter = (*doltTablePartitionIter)(nil)
-
-// doltTablePartitionIter, an object that knows how to return the single partition exactly once.
-type doltTablePartitionIter struct {
-	i          int
-	mu         *sync.Mutex
-	rowData    types.Map
-	partitions []doltTablePartition
-}
-
-func newDoltTablePartitionIter(rowData types.Map, partitions ...doltTablePartition) *doltTablePartitionIter {
-	return &doltTablePartitionIter{0, &sync.Mutex{}, rowData, partitions}
-}
-
-// Close is required by the sql.PartitionIter interface. Does nothing.
-func (itr *doltTablePartitionIter) Close() error {
-	return nil
-}
-
-// Next returns the next partition if there is one, or io.EOF if there isn't.
-func (itr *doltTablePartitionIter) Next() (sql.Partition, error) {
-	itr.mu.Lock()
-	defer itr.mu.Unlock()
-
-	if itr.i >= len(itr.partitions) {
-		return nil, io.EOF
-	}
-
-	partition := itr.partitions[itr.i]
-	itr.i++
-
-	return partition, nil
-}
-
-var _ sql.Partition = (*doltTablePartition)(nil)
-
-type doltTablePartition struct {
-	// start is the first index of this partition (inclusive)
-	start uint64
-	// all elements in the partition will be less than end (exclusive)
-	end uint64
-}
-
-// Key returns the key for this partition, which must uniquely identity the partition.
-func (p doltTablePartition) Key() []byte {
-	return []byte(strconv.FormatUint(p.start, 10) + " >= i < " + strconv.FormatUint(p.end, 10))
-}
-
 // AlterableDoltTable allows altering the schema of the table. It implements sql.AlterableTable.
 type AlterableDoltTable struct {
 	WritableDoltTable
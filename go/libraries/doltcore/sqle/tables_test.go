@@ -15,12 +15,200 @@
 package sqle
 
 import (
+	"context"
+	"io"
 	"testing"
 
+	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 func TestMinRowsPerPartitionInTests(t *testing.T) {
 	// If this fails then the method for determining if we are running in a test doesn't work all the time.
 	assert.Equal(t, uint64(2), MinRowsPerPartition)
 }
+
+func TestDoltTablePartitionsKeyless(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	vrw, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_Default, nil, nil)
+	require.NoError(t, err)
+
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("c0", 0, types.IntKind, false),
+		schema.NewColumn("c1", 1, types.IntKind, false),
+	)
+	require.NoError(t, err)
+	sch, err := schema.SchemaFromCols(coll)
+	require.NoError(t, err)
+
+	// 20 physical rows with 1 to 20 copies each
+	emptyMap, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	me := emptyMap.Edit()
+	for i := 0; i < 20; i++ {
+		r, err := row.NewKeylessRow(vrw.Format(), sch, row.TaggedValues{0: types.Int(i), 1: types.Int(i)}, uint64(i+1))
+		require.NoError(t, err)
+		me.Set(r.NomsMapKey(sch), r.NomsMapValue(sch))
+	}
+	rowData, err := me.Map(ctx)
+	require.NoError(t, err)
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData, emptyMap)
+	require.NoError(t, err)
+
+	dt := NewDoltTable("keyless", sch, tbl, nil)
+	sqlCtx := sql.NewEmptyContext()
+
+	countRows := func(p sql.Partition) int {
+		iter, err := dt.PartitionRows(sqlCtx, p)
+		require.NoError(t, err)
+		defer iter.Close()
+
+		count := 0
+		for {
+			_, err := iter.Next()
+			if err == io.EOF {
+				return count
+			}
+			require.NoError(t, err)
+			count++
+		}
+	}
+
+	expected := countRows(sqlutil.SinglePartition{})
+	assert.Equal(t, 210, expected)
+
+	partitions, err := dt.Partitions(sqlCtx)
+	require.NoError(t, err)
+
+	numPartitions := 0
+	actual := 0
+	for {
+		p, err := partitions.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		numPartitions++
+		actual += countRows(p)
+	}
+	require.NoError(t, partitions.Close())
+
+	assert.Greater(t, numPartitions, 1)
+	assert.Equal(t, expected, actual)
+}
+
+func newPartitionTestTable(t *testing.T, numRows int) *DoltTable {
+	ctx := context.Background()
+	vrw, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_Default, nil, nil)
+	require.NoError(t, err)
+
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("c1", 1, types.IntKind, false),
+	)
+	require.NoError(t, err)
+	sch, err := schema.SchemaFromCols(coll)
+	require.NoError(t, err)
+
+	emptyMap, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	me := emptyMap.Edit()
+	for i := 0; i < numRows; i++ {
+		r, err := row.New(vrw.Format(), sch, row.TaggedValues{0: types.Int(i), 1: types.Int(i)})
+		require.NoError(t, err)
+		me.Set(r.NomsMapKey(sch), r.NomsMapValue(sch))
+	}
+	rowData, err := me.Map(ctx)
+	require.NoError(t, err)
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData, emptyMap)
+	require.NoError(t, err)
+
+	return NewDoltTable("test", sch, tbl, nil)
+}
+
+// partitionRowCounts reads every partition returned by |partitions| from |dt|, and returns the number of rows read from
+// each of them.
+func partitionRowCounts(t *testing.T, dt *DoltTable, partitions sql.PartitionIter) []int {
+	sqlCtx := sql.NewEmptyContext()
+
+	var counts []int
+	for {
+		p, err := partitions.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		iter, err := dt.PartitionRows(sqlCtx, p)
+		require.NoError(t, err)
+
+		count := 0
+		for {
+			_, err := iter.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			count++
+		}
+		require.NoError(t, iter.Close())
+
+		counts = append(counts, count)
+	}
+	require.NoError(t, partitions.Close())
+
+	return counts
+}
+
+func TestDoltTablePartitions(t *testing.T) {
+	sqlCtx := sql.NewEmptyContext()
+
+	t.Run("empty table", func(t *testing.T) {
+		dt := newPartitionTestTable(t, 0)
+		partitions, err := dt.Partitions(sqlCtx)
+		require.NoError(t, err)
+		assert.Equal(t, []int{0}, partitionRowCounts(t, dt, partitions))
+	})
+
+	t.Run("fewer rows than partitions", func(t *testing.T) {
+		dt := newPartitionTestTable(t, 3)
+		partitions, err := table.NewPartitionIter(sqlCtx, dt.table, 5)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 1, 1}, partitionRowCounts(t, dt, partitions))
+	})
+
+	t.Run("rows spread evenly", func(t *testing.T) {
+		dt := newPartitionTestTable(t, 10)
+		partitions, err := table.NewPartitionIter(sqlCtx, dt.table, 4)
+		require.NoError(t, err)
+		assert.Equal(t, []int{3, 3, 2, 2}, partitionRowCounts(t, dt, partitions))
+
+		partitions, err = dt.Partitions(sqlCtx)
+		require.NoError(t, err)
+		counts := partitionRowCounts(t, dt, partitions)
+
+		total := 0
+		for _, count := range counts {
+			assert.InDelta(t, counts[0], count, 1)
+			total += count
+		}
+		assert.Equal(t, 10, total)
+	})
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 )
 
 // Executes all the SQL non-select statements given in the string against the root value given and returns the updated
@@ -55,7 +56,7 @@ func ExecuteSql(dEnv *env.DoltEnv, root *doltdb.RootValue, statements string) (*
 			return nil, errors.New("Show statements aren't handled")
 		case *sqlparser.Select, *sqlparser.OtherRead:
 			return nil, errors.New("Select statements aren't handled")
-		case *sqlparser.Insert:
+		case *sqlparser.Insert, *sqlparser.Update:
 			var rowIter sql.RowIter
 			_, rowIter, execErr = engine.Query(ctx, query)
 			if execErr == nil {
@@ -86,6 +87,33 @@ func ExecuteSql(dEnv *env.DoltEnv, root *doltdb.RootValue, statements string) (*
 	}
 }
 
+// ExecuteSqlOnBranch is like ExecuteSql, but runs the statements against the working root of the branch named
+// |branchName| rather than a given root. Only the checked out branch has a working root that can differ from its head,
+// so uncommitted changes are seen when |branchName| is checked out, and the root of the head commit is used for any
+// other branch. The updated root is returned and neither the branch nor the working set are changed, so callers must
+// update them themselves. Returns doltdb.ErrBranchNotFound if the branch does not exist.
+func ExecuteSqlOnBranch(dEnv *env.DoltEnv, branchName string, statements string) (*doltdb.RootValue, error) {
+	ctx := context.Background()
+
+	branchRef := ref.NewBranchRef(branchName)
+	cm, err := dEnv.DoltDB.ResolveRef(ctx, branchRef)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *doltdb.RootValue
+	if ref.Equals(branchRef, dEnv.RepoState.CWBHeadRef()) {
+		root, err = dEnv.WorkingRoot(ctx)
+	} else {
+		root, err = cm.GetRootValue()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ExecuteSql(dEnv, root, statements)
+}
+
 // NewTestSQLCtx returns a new *sql.Context with a default DoltSession, a new IndexRegistry, and a new ViewRegistry
 func NewTestSQLCtx(ctx context.Context) *sql.Context {
 	sqlCtx := sql.NewContext(
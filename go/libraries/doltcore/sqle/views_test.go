@@ -54,3 +54,48 @@ func TestViews(t *testing.T) {
 	root, err = ExecuteSql(dEnv, root, "drop view plus1")
 	require.NoError(t, err)
 }
+
+func TestUpdateView(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+
+	ctx := context.Background()
+	root, _ := dEnv.WorkingRoot(ctx)
+
+	var err error
+	root, err = ExecuteSql(dEnv, root, "create table test (a int primary key, b int)")
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, "insert into test values (1, 1), (2, 2), (3, 3)")
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, "create view small as select * from test where a < 3")
+	require.NoError(t, err)
+
+	// views are not updatable, and the update must not reach the underlying table
+	updated, err := ExecuteSql(dEnv, root, "update small set b = 0")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "Unsupported SQL statement")
+	assert.Nil(t, updated)
+
+	expectedRows := []sql.Row{
+		{int64(1), int64(1)},
+		{int64(2), int64(2)},
+		{int64(3), int64(3)},
+	}
+	rows, _, err := executeSelect(context.Background(), dEnv, root, "select * from test")
+	require.NoError(t, err)
+	assert.Equal(t, expectedRows, rows)
+
+	// the same update against the table itself succeeds, so the error above is specific to the view
+	updated, err = ExecuteSql(dEnv, root, "update test set b = 0 where a < 3")
+	require.NoError(t, err)
+
+	expectedRows = []sql.Row{
+		{int64(1), int64(0)},
+		{int64(2), int64(0)},
+		{int64(3), int64(3)},
+	}
+	rows, _, err = executeSelect(context.Background(), dEnv, updated, "select * from test")
+	require.NoError(t, err)
+	assert.Equal(t, expectedRows, rows)
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// TableChecksum reads every row from |rdr| and returns a checksum of the rows' values. Rows are hashed individually
+// and their hashes are sorted before being combined, so the checksum depends only on the multiset of rows read, not
+// on the order the reader returns them in. Each copy of a keyless row is a separate row, so rows that differ only in
+// cardinality have different checksums. Each row is hashed as its encoded tagged values, so columns are identified by
+// tag and null values are omitted, as in NewRowEncoder. The reader is not closed.
+func TableChecksum(ctx context.Context, rdr SqlTableReader) ([]byte, error) {
+	var rowHashes []hash.Hash
+	for {
+		r, err := rdr.ReadRow(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		tup, err := taggedValuesTuple(r)
+		if err != nil {
+			return nil, err
+		}
+
+		h, err := tup.Hash(r.Format())
+		if err != nil {
+			return nil, err
+		}
+
+		rowHashes = append(rowHashes, h)
+	}
+
+	sort.Slice(rowHashes, func(i, j int) bool {
+		return rowHashes[i].Less(rowHashes[j])
+	})
+
+	data := make([]byte, 0, len(rowHashes)*hash.ByteLen)
+	for _, h := range rowHashes {
+		data = append(data, h[:]...)
+	}
+
+	checksum := hash.Of(data)
+	return checksum[:], nil
+}
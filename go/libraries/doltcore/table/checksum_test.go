@@ -0,0 +1,92 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+func TestTableChecksum(t *testing.T) {
+	ctx := context.Background()
+
+	checksum := func(t *testing.T, tbl *doltdb.Table) []byte {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		sum, err := TableChecksum(ctx, rdr)
+		require.NoError(t, err)
+		return sum
+	}
+
+	t.Run("keyed", func(t *testing.T) {
+		tbl := newKeyedTestTable(t, 10)
+		sum := checksum(t, tbl)
+		assert.Equal(t, sum, checksum(t, newKeyedTestTable(t, 10)))
+		assert.NotEqual(t, sum, checksum(t, newKeyedTestTable(t, 9)))
+
+		// the checksum does not depend on the order rows are read in
+		fwd, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		rev, err := NewReverseTableReader(ctx, tbl)
+		require.NoError(t, err)
+		fwdSum, err := TableChecksum(ctx, fwd)
+		require.NoError(t, err)
+		revSum, err := TableChecksum(ctx, rev)
+		require.NoError(t, err)
+		assert.Equal(t, fwdSum, revSum)
+		assert.Equal(t, sum, fwdSum)
+	})
+
+	t.Run("keyless", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		sch := mustKeylessSchema(t)
+		rows := []keylessTestRow{
+			{c0: 0, c1: 0, card: 1},
+			{c0: 1, c1: 1, card: 2},
+			{c0: 2, c1: 2, card: 3},
+		}
+		sum := checksum(t, newKeylessTestTable(t, sch, rows...))
+
+		reordered := []keylessTestRow{rows[2], rows[0], rows[1]}
+		assert.Equal(t, sum, checksum(t, newKeylessTestTable(t, sch, reordered...)))
+
+		changedValue := []keylessTestRow{rows[0], rows[1], {c0: 2, c1: 3, card: 3}}
+		assert.NotEqual(t, sum, checksum(t, newKeylessTestTable(t, sch, changedValue...)))
+
+		changedCard := []keylessTestRow{rows[0], rows[1], {c0: 2, c1: 2, card: 4}}
+		assert.NotEqual(t, sum, checksum(t, newKeylessTestTable(t, sch, changedCard...)))
+	})
+	t.Run("decoded rows", func(t *testing.T) {
+		// rows are hashed in the same encoding NewRowEncoder writes, so a decoded stream has the same checksum
+		tbl := newKeyedTestTable(t, 10)
+		sch, err := tbl.GetSchema(ctx)
+		require.NoError(t, err)
+
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		dec := NewRowDecoder(NewRowEncoder(ctx, rdr), sch, tbl.ValueReadWriter())
+		decSum, err := TableChecksum(ctx, dec)
+		require.NoError(t, err)
+		assert.Equal(t, checksum(t, tbl), decSum)
+	})
+}
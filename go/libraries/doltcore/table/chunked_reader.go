@@ -0,0 +1,71 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+)
+
+var ErrInvalidChunkSize = errors.New("chunk size must be greater than 0")
+
+// ChunkedReader is a SqlTableReader which can also return the rows of another reader in chunks of a fixed size, for
+// batch oriented consumers. Each copy of a keyless row counts as a row, so a chunk can end partway through the
+// copies of a row, with the rest returned in the next chunk. Rows can be read individually between chunks.
+type ChunkedReader struct {
+	SqlTableReader
+}
+
+var _ SqlTableReader = &ChunkedReader{}
+
+// NewChunkedReader creates a ChunkedReader over the rows of |rdr|.
+func NewChunkedReader(rdr SqlTableReader) *ChunkedReader {
+	return &ChunkedReader{SqlTableReader: rdr}
+}
+
+// NextChunk returns the next |n| rows. Only the last chunk can have fewer than |n| rows, and once every row has been
+// returned, NextChunk returns io.EOF. If reading a row fails, the error is returned and the rows read before it in
+// the chunk are discarded.
+func (rd *ChunkedReader) NextChunk(ctx context.Context, n int) ([]row.Row, error) {
+	if n <= 0 {
+		return nil, ErrInvalidChunkSize
+	}
+
+	var chunk []row.Row
+	for len(chunk) < n {
+		r, err := rd.SqlTableReader.ReadRow(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		chunk = append(chunk, r)
+	}
+
+	if len(chunk) == 0 {
+		return nil, io.EOF
+	}
+
+	return chunk, nil
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *ChunkedReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
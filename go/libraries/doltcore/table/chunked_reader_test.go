@@ -0,0 +1,110 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestChunkedReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	tbl := newKeylessTestTable(t, mustKeylessSchema(t),
+		keylessTestRow{c0: 1, c1: 1, card: 1},
+		keylessTestRow{c0: 2, c1: 2, card: 7},
+		keylessTestRow{c0: 3, c1: 3, card: 4},
+	)
+
+	tests := []struct {
+		n      int
+		chunks []int
+	}{
+		{n: 1, chunks: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+		{n: 3, chunks: []int{3, 3, 3, 3}},
+		{n: 5, chunks: []int{5, 5, 2}},
+		{n: 12, chunks: []int{12}},
+		{n: 20, chunks: []int{12}},
+	}
+
+	for _, test := range tests {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		crd := NewChunkedReader(rdr)
+
+		var sizes []int
+		counts := make(map[int64]uint64)
+		for {
+			chunk, err := crd.NextChunk(ctx, test.n)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			sizes = append(sizes, len(chunk))
+			for _, r := range chunk {
+				c0, ok := r.GetColVal(keylessC0Tag)
+				require.True(t, ok)
+				counts[int64(c0.(types.Int))]++
+			}
+		}
+
+		assert.Equal(t, test.chunks, sizes, "chunk size %d", test.n)
+		assert.Equal(t, map[int64]uint64{1: 1, 2: 7, 3: 4}, counts, "chunk size %d", test.n)
+
+		_, err = crd.NextChunk(ctx, test.n)
+		assert.Equal(t, io.EOF, err)
+		require.NoError(t, crd.Close(ctx))
+	}
+
+	t.Run("keyed rows in order", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, newKeyedTestTable(t, 10))
+		require.NoError(t, err)
+		crd := NewChunkedReader(rdr)
+		defer crd.Close(ctx)
+
+		var pk int64
+		for _, size := range []int{4, 4, 2} {
+			chunk, err := crd.NextChunk(ctx, 4)
+			require.NoError(t, err)
+			require.Len(t, chunk, size)
+			for _, r := range chunk {
+				val, ok := r.GetColVal(0)
+				require.True(t, ok)
+				assert.Equal(t, types.Int(pk), val)
+				pk++
+			}
+		}
+
+		_, err = crd.NextChunk(ctx, 4)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("invalid chunk size", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		_, err = NewChunkedReader(rdr).NextChunk(ctx, 0)
+		assert.Equal(t, ErrInvalidChunkSize, err)
+	})
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+)
+
+// CoalescingReader merges runs of identical rows read from another reader into a single row whose cardinality is
+// the sum of the run's cardinalities. This keeps keyless rows compact after a projection has made physical rows
+// that differed only in the dropped columns identical. Rows are compared on every column of the reader's schema,
+// with NULL equal to NULL, and only adjacent rows are merged, so the input should be sorted on all of its columns,
+// for example with WithOrderBy. ReadRowWithCardinality returns each merged row with its combined cardinality.
+// ReadRow and ReadSqlRow return each merged row once, discarding its cardinality.
+type CoalescingReader struct {
+	SqlTableReader
+	cardRdr cardinalityReader
+	tags    []uint64
+	conv    *row.SqlRowConverter
+
+	// next is the first row of the next run, read but not yet returned, and nextCard is its cardinality.
+	next     row.Row
+	nextCard uint64
+	done     bool
+}
+
+var _ SqlTableReader = &CoalescingReader{}
+var _ cardinalityReader = &CoalescingReader{}
+
+// NewCoalescingReader creates a CoalescingReader over the rows of |rdr|. If |rdr| can return rows with their
+// cardinality, as keyless table readers and readers created with WithOrderBy can, every copy of a row is counted
+// without being read individually.
+func NewCoalescingReader(rdr SqlTableReader) *CoalescingReader {
+	sch := rdr.GetSchema()
+	cardRdr, _ := rdr.(cardinalityReader)
+
+	return &CoalescingReader{
+		SqlTableReader: rdr,
+		cardRdr:        cardRdr,
+		tags:           sch.GetAllCols().Tags,
+		conv:           row.NewSqlRowConverter(sch),
+	}
+}
+
+// ReadRowWithCardinality reads the next run of identical rows, returning the row and the total number of copies of
+// it in the run.
+func (rd *CoalescingReader) ReadRowWithCardinality(ctx context.Context) (row.Row, uint64, error) {
+	if rd.next == nil {
+		if rd.done {
+			return nil, 0, io.EOF
+		}
+
+		var err error
+		rd.next, rd.nextCard, err = rd.readUnderlying(ctx)
+		if err != nil {
+			return nil, 0, rd.checkEOF(err)
+		}
+	}
+
+	r, card := rd.next, rd.nextCard
+	rd.next = nil
+
+	for {
+		nr, nc, err := rd.readUnderlying(ctx)
+		if err == io.EOF {
+			rd.done = true
+			return r, card, nil
+		} else if err != nil {
+			return nil, 0, err
+		}
+
+		cmp, err := compareRowsByTags(r, nr, rd.tags)
+		if err != nil {
+			return nil, 0, err
+		} else if cmp != 0 {
+			rd.next, rd.nextCard = nr, nc
+			return r, card, nil
+		}
+
+		card += nc
+	}
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *CoalescingReader) ReadRow(ctx context.Context) (row.Row, error) {
+	r, _, err := rd.ReadRowWithCardinality(ctx)
+	return r, err
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *CoalescingReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.conv.Convert(r)
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *CoalescingReader) Close(ctx context.Context) error {
+	rd.next = nil
+	return closeReader(ctx, rd.SqlTableReader)
+}
+
+// readUnderlying reads a row and its cardinality from the underlying reader.
+func (rd *CoalescingReader) readUnderlying(ctx context.Context) (row.Row, uint64, error) {
+	if rd.cardRdr != nil {
+		return rd.cardRdr.ReadRowWithCardinality(ctx)
+	}
+
+	r, err := rd.SqlTableReader.ReadRow(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return r, 1, nil
+}
+
+// checkEOF records that the underlying reader has no more rows.
+func (rd *CoalescingReader) checkEOF(err error) error {
+	if err == io.EOF {
+		rd.done = true
+	}
+
+	return err
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCoalescingReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 1, c1: 1, card: 2},
+		keylessTestRow{c0: 1, c1: 2, card: 3},
+		keylessTestRow{c0: 2, c1: 2, card: 1},
+		keylessTestRow{c0: 0, c1: 5, card: 4},
+	)
+
+	type c0Card struct {
+		c0   int64
+		card uint64
+	}
+	readAll := func(t *testing.T, rd *CoalescingReader) []c0Card {
+		var rows []c0Card
+		for {
+			r, card, err := rd.ReadRowWithCardinality(ctx)
+			if err == io.EOF {
+				return rows
+			}
+			require.NoError(t, err)
+
+			c0, ok := r.GetColVal(keylessC0Tag)
+			require.True(t, ok)
+			rows = append(rows, c0Card{c0: int64(c0.(types.Int)), card: card})
+		}
+	}
+
+	t.Run("projected rows are merged", func(t *testing.T) {
+		rdr, err := NewTableReaderWithProjection(ctx, tbl, []uint64{keylessC0Tag}, WithOrderBy(keylessC0Tag))
+		require.NoError(t, err)
+		rd := NewCoalescingReader(rdr)
+		defer rd.Close(ctx)
+
+		assert.Equal(t, []c0Card{{0, 4}, {1, 5}, {2, 1}}, readAll(t, rd))
+
+		_, _, err = rd.ReadRowWithCardinality(ctx)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("distinct rows are not merged", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl, WithOrderBy(keylessC0Tag, keylessC1Tag))
+		require.NoError(t, err)
+		rd := NewCoalescingReader(rdr)
+		defer rd.Close(ctx)
+
+		assert.Equal(t, []c0Card{{0, 4}, {1, 2}, {1, 3}, {2, 1}}, readAll(t, rd))
+	})
+
+	t.Run("read sql rows", func(t *testing.T) {
+		rdr, err := NewTableReaderWithProjection(ctx, tbl, []uint64{keylessC0Tag}, WithOrderBy(keylessC0Tag))
+		require.NoError(t, err)
+		rd := NewCoalescingReader(rdr)
+		defer rd.Close(ctx)
+
+		rows := readAllSqlRows(t, rd)
+		require.Len(t, rows, 3)
+		for i, r := range rows {
+			assert.Equal(t, int64(i), r[0])
+		}
+	})
+
+	t.Run("reader without cardinality", func(t *testing.T) {
+		coll, err := schema.NewColCollection(schema.NewColumn("c0", keylessC0Tag, types.IntKind, false))
+		require.NoError(t, err)
+		imtSch := schema.UnkeyedSchemaFromCols(coll)
+
+		var rows []row.Row
+		for _, v := range []int64{3, 3, 3, 4, 5, 5} {
+			r, err := row.New(types.Format_Default, imtSch, row.TaggedValues{keylessC0Tag: types.Int(v)})
+			require.NoError(t, err)
+			rows = append(rows, r)
+		}
+
+		rd := NewCoalescingReader(NewInMemTableReader(NewInMemTableWithData(imtSch, rows)))
+		assert.Equal(t, []c0Card{{3, 3}, {4, 1}, {5, 2}}, readAll(t, rd))
+	})
+}
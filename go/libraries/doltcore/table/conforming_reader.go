@@ -0,0 +1,128 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+)
+
+// SchemaConformingReader is a SqlTableReader which reads rows written with an older schema and returns them conformed
+// to a newer target schema. Columns are matched by tag. Columns of the target schema that the underlying reader's
+// schema does not have are filled with their default values, or null if they have no default. Columns that were
+// dropped from the target schema are discarded.
+type SchemaConformingReader struct {
+	rdr SqlTableReader
+	sch schema.Schema
+
+	sqlSch      sql.Schema
+	defaultIdxs []int
+}
+
+var _ SqlTableReader = &SchemaConformingReader{}
+
+// NewSchemaConformingReader creates a SchemaConformingReader returning the rows of |rdr| with the schema |targetSch|.
+// The types of columns shared by both schemas must not have changed.
+func NewSchemaConformingReader(rdr SqlTableReader, targetSch schema.Schema) (*SchemaConformingReader, error) {
+	sqlSch, err := sqlutil.FromDoltSchema("", targetSch)
+	if err != nil {
+		return nil, err
+	}
+
+	srcCols := rdr.GetSchema().GetAllCols()
+
+	var defaultIdxs []int
+	for i, tag := range targetSch.GetAllCols().Tags {
+		if _, ok := srcCols.GetByTag(tag); !ok && sqlSch[i].Default != nil {
+			defaultIdxs = append(defaultIdxs, i)
+		}
+	}
+
+	return &SchemaConformingReader{
+		rdr:         rdr,
+		sch:         targetSch,
+		sqlSch:      sqlSch,
+		defaultIdxs: defaultIdxs,
+	}, nil
+}
+
+// GetSchema implements the TableReader interface. It returns the target schema.
+func (rd *SchemaConformingReader) GetSchema() schema.Schema {
+	return rd.sch
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *SchemaConformingReader) ReadRow(ctx context.Context) (row.Row, error) {
+	r, err := rd.rdr.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.conform(ctx, r)
+}
+
+// ReadSqlRow implements the SqlTableReader interface. The returned row has one field for each column of the target
+// schema.
+func (rd *SchemaConformingReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return row.DoltRowToSqlRow(r, rd.sch)
+}
+
+func (rd *SchemaConformingReader) conform(ctx context.Context, r row.Row) (row.Row, error) {
+	taggedVals := make(row.TaggedValues)
+	for _, tag := range rd.sch.GetAllCols().Tags {
+		if val, ok := r.GetColVal(tag); ok {
+			taggedVals[tag] = val
+		}
+	}
+
+	if len(rd.defaultIdxs) > 0 {
+		conformed, err := row.New(r.Format(), rd.sch, taggedVals)
+		if err != nil {
+			return nil, err
+		}
+
+		conformed, err = sqlutil.ApplyDefaults(ctx, rd.sch, rd.sqlSch, rd.defaultIdxs, conformed)
+		if err != nil || !schema.IsKeyless(rd.sch) {
+			return conformed, err
+		}
+
+		// sqlutil.ApplyDefaults always builds a keyed row, so keyless rows are rebuilt from its values
+		taggedVals, err = row.GetTaggedVals(conformed)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if schema.IsKeyless(rd.sch) {
+		return row.NewKeylessRow(r.Format(), rd.sch, taggedVals, 1)
+	}
+
+	return row.New(r.Format(), rd.sch, taggedVals)
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *SchemaConformingReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.rdr)
+}
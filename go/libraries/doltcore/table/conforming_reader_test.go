@@ -0,0 +1,116 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestSchemaConformingReader(t *testing.T) {
+	ctx := context.Background()
+	tbl := newKeyedTestTable(t, 3)
+
+	// the target schema gained two columns after the rows were written, one with a default and one without
+	c2, err := schema.NewColumnWithTypeInfo("c2", 2, typeinfo.Int64Type, false, "42", false, "")
+	require.NoError(t, err)
+	c3, err := schema.NewColumnWithTypeInfo("c3", 3, typeinfo.StringDefaultType, false, "", false, "")
+	require.NoError(t, err)
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("val", 1, types.IntKind, false),
+		c2,
+		c3,
+	)
+	require.NoError(t, err)
+	targetSch, err := schema.SchemaFromCols(coll)
+	require.NoError(t, err)
+
+	t.Run("sql rows", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		crdr, err := NewSchemaConformingReader(rdr, targetSch)
+		require.NoError(t, err)
+		assert.Equal(t, targetSch, crdr.GetSchema())
+
+		expected := []sql.Row{
+			{int64(0), int64(0), int64(42), nil},
+			{int64(1), int64(10), int64(42), nil},
+			{int64(2), int64(20), int64(42), nil},
+		}
+		assert.Equal(t, expected, readAllSqlRows(t, crdr))
+	})
+
+	t.Run("dolt rows", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		crdr, err := NewSchemaConformingReader(rdr, targetSch)
+		require.NoError(t, err)
+
+		r, err := crdr.ReadRow(ctx)
+		require.NoError(t, err)
+		v, ok := r.GetColVal(2)
+		require.True(t, ok)
+		assert.Equal(t, types.Int(42), v)
+		_, ok = r.GetColVal(3)
+		assert.False(t, ok)
+	})
+	t.Run("keyless rows", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		keylessTbl := newKeylessTestTable(t, mustKeylessSchema(t), keylessTestRow{c0: 1, c1: 2, card: 2})
+
+		c2, err := schema.NewColumnWithTypeInfo("c2", 2, typeinfo.Int64Type, false, "7", false, "")
+		require.NoError(t, err)
+		coll, err := schema.NewColCollection(
+			schema.NewColumn("c0", keylessC0Tag, types.IntKind, false),
+			schema.NewColumn("c1", keylessC1Tag, types.IntKind, false),
+			c2,
+		)
+		require.NoError(t, err)
+		keylessSch, err := schema.SchemaFromCols(coll)
+		require.NoError(t, err)
+
+		rdr, err := NewTableReader(ctx, keylessTbl)
+		require.NoError(t, err)
+		crdr, err := NewSchemaConformingReader(rdr, keylessSch)
+		require.NoError(t, err)
+
+		// each copy is returned as its own keyless row with the default applied
+		for i := 0; i < 2; i++ {
+			r, err := crdr.ReadRow(ctx)
+			require.NoError(t, err)
+			v, ok := r.GetColVal(2)
+			require.True(t, ok)
+			assert.Equal(t, types.Int(7), v)
+
+			val, err := r.NomsMapValue(keylessSch).Value(ctx)
+			require.NoError(t, err)
+			card, err := row.KeylessCardinality(val.(types.Tuple))
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1), card)
+		}
+	})
+}
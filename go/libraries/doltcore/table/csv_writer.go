@@ -0,0 +1,151 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// CSVOptions configures the output of WriteCSV.
+type CSVOptions struct {
+	// Delim separates the fields of a record
+	Delim string
+	// NullValue is written for NULL fields
+	NullValue string
+	// HasHeaderLine says whether a line of column names is written before the rows
+	HasHeaderLine bool
+}
+
+// NewCSVOptions creates CSVOptions for comma separated output with a header line, where NULLs are written as empty
+// fields. This matches the defaults of the csv import reader.
+func NewCSVOptions() *CSVOptions {
+	return &CSVOptions{Delim: ",", NullValue: "", HasHeaderLine: true}
+}
+
+// WriteCSV writes the rows of |rdr| to |w| as RFC 4180 CSV. Values are formatted using the TypeInfo of their column
+// in the reader's schema. Non-NULL fields that would otherwise read back as NULL, such as an empty string when
+// NULLs are written as empty fields, are quoted. |rdr| is read until io.EOF, but is not closed.
+func WriteCSV(ctx context.Context, w io.Writer, rdr SqlTableReader, opts *CSVOptions) error {
+	if opts == nil {
+		opts = NewCSVOptions()
+	}
+	if opts.Delim == "" {
+		return errors.New("csv delimiter cannot be empty")
+	}
+
+	bw := bufio.NewWriter(w)
+	cols := rdr.GetSchema().GetAllCols()
+	fields := make([]*string, cols.Size())
+
+	if opts.HasHeaderLine {
+		for i, name := range cols.GetColumnNames() {
+			name := name
+			fields[i] = &name
+		}
+
+		if err := writeCSVRecord(bw, fields, opts); err != nil {
+			return err
+		}
+	}
+
+	for {
+		r, err := rdr.ReadSqlRow(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		err = cols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+			i := cols.TagToIdx[tag]
+			if r[i] == nil {
+				fields[i] = nil
+				return false, nil
+			}
+
+			val, err := col.TypeInfo.ConvertValueToNomsValue(r[i])
+			if err != nil {
+				return true, err
+			}
+
+			fields[i], err = col.TypeInfo.FormatValue(val)
+			return err != nil, err
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = writeCSVRecord(bw, fields, opts); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// writeCSVRecord writes a single line of |fields|, where a nil field is NULL.
+func writeCSVRecord(bw *bufio.Writer, fields []*string, opts *CSVOptions) error {
+	for i, field := range fields {
+		if i > 0 {
+			if _, err := bw.WriteString(opts.Delim); err != nil {
+				return err
+			}
+		}
+
+		if field == nil {
+			if _, err := bw.WriteString(opts.NullValue); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !csvFieldNeedsQuotes(*field, opts) {
+			if _, err := bw.WriteString(*field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		quoted := `"` + strings.ReplaceAll(*field, `"`, `""`) + `"`
+		if _, err := bw.WriteString(quoted); err != nil {
+			return err
+		}
+	}
+
+	return bw.WriteByte('\n')
+}
+
+// csvFieldNeedsQuotes reports whether a non-NULL |field| must be quoted. In addition to the RFC 4180 cases, fields
+// that match the NULL representation and fields with leading whitespace are quoted.
+func csvFieldNeedsQuotes(field string, opts *CSVOptions) bool {
+	if field == opts.NullValue {
+		return true
+	}
+
+	if strings.Contains(field, opts.Delim) || strings.ContainsAny(field, "\"\r\n") {
+		return true
+	}
+
+	r1, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r1)
+}
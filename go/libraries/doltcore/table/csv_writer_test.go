@@ -0,0 +1,136 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	csvIdTag uint64 = iota
+	csvNameTag
+	csvScoreTag
+)
+
+func newCSVTestTable(t *testing.T) *table.InMemTable {
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("id", csvIdTag, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("name", csvNameTag, types.StringKind, false),
+		schema.NewColumn("score", csvScoreTag, types.FloatKind, false),
+	)
+	require.NoError(t, err)
+	sch, err := schema.SchemaFromCols(coll)
+	require.NoError(t, err)
+
+	vals := []row.TaggedValues{
+		{csvIdTag: types.Int(0), csvNameTag: types.String("plain"), csvScoreTag: types.Float(1.5)},
+		{csvIdTag: types.Int(1), csvNameTag: types.String("comma, separated"), csvScoreTag: types.Float(-2)},
+		{csvIdTag: types.Int(2), csvNameTag: types.String(`"quoted"`)},
+		{csvIdTag: types.Int(3), csvNameTag: types.String("multi\nline"), csvScoreTag: types.Float(0)},
+		{csvIdTag: types.Int(4), csvNameTag: types.String("")},
+		{csvIdTag: types.Int(5), csvNameTag: types.String(" leading space"), csvScoreTag: types.Float(100.25)},
+	}
+
+	var rows []row.Row
+	for _, tv := range vals {
+		r, err := row.New(types.Format_Default, sch, tv)
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+
+	return table.NewInMemTableWithData(sch, rows)
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	imt := newCSVTestTable(t)
+	sch := imt.GetSchema()
+
+	buf := &bytes.Buffer{}
+	err := table.WriteCSV(ctx, buf, table.NewInMemTableReader(imt), table.NewCSVOptions())
+	require.NoError(t, err)
+
+	rdr, err := csv.NewCSVReader(types.Format_Default, ioutil.NopCloser(buf), csv.NewCSVInfo())
+	require.NoError(t, err)
+	defer rdr.Close(ctx)
+
+	assert.Equal(t, sch.GetAllCols().GetColumnNames(), rdr.GetSchema().GetAllCols().GetColumnNames())
+
+	var n int
+	for ; ; n++ {
+		r, err := rdr.ReadRow(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		expected, err := imt.GetRow(n)
+		require.NoError(t, err)
+
+		for i, col := range sch.GetAllCols().GetColumns() {
+			expectedVal, _ := expected.GetColVal(col.Tag)
+
+			var str *string
+			if v, ok := r.GetColVal(uint64(i)); ok && !types.IsNull(v) {
+				s := string(v.(types.String))
+				str = &s
+			}
+
+			actualVal, err := col.TypeInfo.ParseValue(str)
+			require.NoError(t, err)
+
+			if types.IsNull(expectedVal) {
+				assert.True(t, types.IsNull(actualVal), "row %d col %s", n, col.Name)
+			} else {
+				assert.Equal(t, expectedVal, actualVal, "row %d col %s", n, col.Name)
+			}
+		}
+	}
+	assert.Equal(t, imt.NumRows(), n)
+}
+
+func TestWriteCSVOptions(t *testing.T) {
+	ctx := context.Background()
+	imt := newCSVTestTable(t)
+
+	buf := &bytes.Buffer{}
+	opts := &table.CSVOptions{Delim: "|", NullValue: `\N`, HasHeaderLine: false}
+	err := table.WriteCSV(ctx, buf, table.NewInMemTableReader(imt), opts)
+	require.NoError(t, err)
+
+	expected := "0|plain|1.5\n" +
+		"1|comma, separated|-2\n" +
+		`2|"""quoted"""|\N` + "\n" +
+		"3|\"multi\nline\"|0\n" +
+		"4||\\N\n" +
+		"5|\" leading space\"|100.25\n"
+	assert.Equal(t, expected, buf.String())
+
+	err = table.WriteCSV(ctx, buf, table.NewInMemTableReader(imt), &table.CSVOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,213 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidDecodeTarget is returned by DecodeInto when its destination is not a pointer to a slice of structs.
+var ErrInvalidDecodeTarget = errors.New("decode destination must be a pointer to a slice of structs")
+
+const decodeTagName = "dolt"
+
+// decodeField is a struct field that a column is decoded into.
+type decodeField struct {
+	name   string
+	index  int
+	colIdx int
+}
+
+// DecodeInto reads every row of |rdr| into |dst|, which must be a pointer to a slice of structs, replacing the
+// slice's contents. Struct fields are matched to columns by a `dolt:"column_name"` tag, compared case-insensitively.
+// Columns without a matching field are ignored, as are fields without a tag or tagged `dolt:"-"`. It is an error
+// for a tagged field to have no matching column, unless it is tagged as optional, as in
+// `dolt:"column_name,optional"`, in which case it is left as its zero value. NULL values can only be decoded into
+// pointer, interface, slice and map fields, which are set to nil. Numeric values are converted to the field's
+// numeric type if they fit, integers are decoded into bool fields as true if they are non-zero, and other values
+// must be assignable to the field. |rdr| is read until io.EOF, but is not closed.
+func DecodeInto(ctx context.Context, rdr SqlTableReader, dst interface{}) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Slice || ptr.Elem().Type().Elem().Kind() != reflect.Struct {
+		return ErrInvalidDecodeTarget
+	}
+
+	slice := ptr.Elem()
+	structType := slice.Type().Elem()
+
+	fields, err := decodeFields(structType, rdr.GetSchema().GetAllCols().GetColumnNames())
+	if err != nil {
+		return err
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, 0)
+	for {
+		r, err := rdr.ReadSqlRow(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		elem := reflect.New(structType).Elem()
+		for _, f := range fields {
+			if err = decodeValue(r[f.colIdx], elem.Field(f.index)); err != nil {
+				return fmt.Errorf("cannot decode column '%s' into field %s: %w", f.name, structType.Field(f.index).Name, err)
+			}
+		}
+
+		result = reflect.Append(result, elem)
+	}
+
+	slice.Set(result)
+
+	return nil
+}
+
+// decodeFields matches the tagged fields of |structType| to the columns named |colNames|.
+func decodeFields(structType reflect.Type, colNames []string) ([]decodeField, error) {
+	colIdx := make(map[string]int, len(colNames))
+	for i, name := range colNames {
+		colIdx[strings.ToLower(name)] = i
+	}
+
+	var fields []decodeField
+	for i := 0; i < structType.NumField(); i++ {
+		sf := structType.Field(i)
+		tag, ok := sf.Tag.Lookup(decodeTagName)
+		if !ok || tag == "-" || sf.PkgPath != "" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			name, opts = tag[:comma], tag[comma+1:]
+		}
+
+		idx, ok := colIdx[strings.ToLower(name)]
+		if !ok {
+			if opts == "optional" {
+				continue
+			}
+			return nil, fmt.Errorf("field %s is tagged with column '%s', which does not exist", sf.Name, name)
+		}
+
+		fields = append(fields, decodeField{name: name, index: i, colIdx: idx})
+	}
+
+	return fields, nil
+}
+
+// decodeValue sets |field| to the sql value |val|.
+func decodeValue(val interface{}, field reflect.Value) error {
+	if val == nil {
+		switch field.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		return fmt.Errorf("cannot decode NULL into type %s", field.Type())
+	}
+
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if err := decodeValue(val, elem.Elem()); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	v := reflect.ValueOf(val)
+	if v.Type().AssignableTo(field.Type()) {
+		field.Set(v)
+		return nil
+	}
+
+	switch {
+	case field.Kind() == reflect.Bool && isIntegerKind(v.Kind()):
+		field.SetBool(!v.IsZero())
+		return nil
+
+	case isIntKind(field.Kind()) && isIntegerKind(v.Kind()):
+		if isUintKind(v.Kind()) {
+			u := v.Uint()
+			if u > math.MaxInt64 || field.OverflowInt(int64(u)) {
+				return fmt.Errorf("value %d overflows type %s", u, field.Type())
+			}
+			field.SetInt(int64(u))
+		} else {
+			if field.OverflowInt(v.Int()) {
+				return fmt.Errorf("value %d overflows type %s", v.Int(), field.Type())
+			}
+			field.SetInt(v.Int())
+		}
+		return nil
+
+	case isUintKind(field.Kind()) && isIntegerKind(v.Kind()):
+		if isUintKind(v.Kind()) {
+			if field.OverflowUint(v.Uint()) {
+				return fmt.Errorf("value %d overflows type %s", v.Uint(), field.Type())
+			}
+			field.SetUint(v.Uint())
+		} else {
+			if v.Int() < 0 || field.OverflowUint(uint64(v.Int())) {
+				return fmt.Errorf("value %d overflows type %s", v.Int(), field.Type())
+			}
+			field.SetUint(uint64(v.Int()))
+		}
+		return nil
+
+	case isFloatKind(field.Kind()) && (isFloatKind(v.Kind()) || isIntegerKind(v.Kind())):
+		field.Set(v.Convert(field.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("cannot decode value of type %s into type %s", v.Type(), field.Type())
+}
+
+// isIntegerKind returns whether |k| is a signed or unsigned integer kind.
+func isIntegerKind(k reflect.Kind) bool {
+	return isIntKind(k) || isUintKind(k)
+}
+
+// isIntKind returns whether |k| is a signed integer kind.
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// isUintKind returns whether |k| is an unsigned integer kind.
+func isUintKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// isFloatKind returns whether |k| is a floating point kind.
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDecodeInto(t *testing.T) {
+	ctx := context.Background()
+
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("name", 1, types.StringKind, false),
+		schema.NewColumn("is_married", 2, types.BoolKind, false),
+		schema.NewColumn("nickname", 3, types.StringKind, false),
+		schema.NewColumn("rating", 4, types.FloatKind, false),
+		schema.NewColumn("episodes", 5, types.UintKind, false),
+	)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(coll)
+
+	newRow := func(vals row.TaggedValues) row.Row {
+		r, err := row.New(types.Format_Default, sch, vals)
+		require.NoError(t, err)
+		return r
+	}
+	rows := []row.Row{
+		newRow(row.TaggedValues{0: types.Int(1), 1: types.String("Homer"), 2: types.Bool(true), 4: types.Float(8.5), 5: types.Uint(200)}),
+		newRow(row.TaggedValues{0: types.Int(2), 1: types.String("Marge"), 2: types.Bool(false), 3: types.String("Midge"), 5: types.Uint(7)}),
+	}
+	newReader := func() SqlTableReader {
+		return NewInMemTableReader(NewInMemTableWithData(sch, rows))
+	}
+
+	type person struct {
+		ID       int32    `dolt:"id"`
+		Name     string   `dolt:"NAME"`
+		Married  bool     `dolt:"is_married"`
+		Nickname *string  `dolt:"nickname"`
+		Rating   *float64 `dolt:"rating"`
+		Episodes int      `dolt:"episodes"`
+		Age      int      `dolt:"age,optional"`
+		Skipped  string   `dolt:"-"`
+		Untagged string
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		people := []person{{ID: 100}}
+		require.NoError(t, DecodeInto(ctx, newReader(), &people))
+
+		midge := "Midge"
+		rating := 8.5
+		expected := []person{
+			{ID: 1, Name: "Homer", Married: true, Rating: &rating, Episodes: 200},
+			{ID: 2, Name: "Marge", Married: false, Nickname: &midge, Episodes: 7},
+		}
+		assert.Equal(t, expected, people)
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		var dst []struct {
+			ID  int `dolt:"id"`
+			Age int `dolt:"age"`
+		}
+		assert.Error(t, DecodeInto(ctx, newReader(), &dst))
+	})
+
+	t.Run("null into non-pointer field", func(t *testing.T) {
+		var dst []struct {
+			Nickname string `dolt:"nickname"`
+		}
+		assert.Error(t, DecodeInto(ctx, newReader(), &dst))
+	})
+
+	t.Run("conversion errors", func(t *testing.T) {
+		var dst []struct {
+			Episodes int8 `dolt:"episodes"`
+		}
+		assert.Error(t, DecodeInto(ctx, newReader(), &dst))
+
+		var bools []struct {
+			Name bool `dolt:"name"`
+		}
+		assert.Error(t, DecodeInto(ctx, newReader(), &bools))
+	})
+
+	t.Run("invalid destination", func(t *testing.T) {
+		var people []person
+		assert.Equal(t, ErrInvalidDecodeTarget, DecodeInto(ctx, newReader(), people))
+		var p person
+		assert.Equal(t, ErrInvalidDecodeTarget, DecodeInto(ctx, newReader(), &p))
+		var ints []int
+		assert.Equal(t, ErrInvalidDecodeTarget, DecodeInto(ctx, newReader(), &ints))
+	})
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ValueDecoder converts a value as it is stored in a table into a value of the type of the column |col|. It is
+// called for every non-null value read.
+type ValueDecoder func(col schema.Column, val types.Value) (types.Value, error)
+
+// DecodeStringValues is a ValueDecoder for tables written by older versions of Dolt, or imported without a schema,
+// whose values are all stored as strings. Each string is parsed as a value of its column's type.
+func DecodeStringValues(col schema.Column, val types.Value) (types.Value, error) {
+	str, ok := val.(types.String)
+	if !ok {
+		return nil, fmt.Errorf("column '%s' has a value of kind %s, expected a string", col.Name, val.Kind().String())
+	}
+
+	s := string(str)
+	return col.TypeInfo.ParseValue(&s)
+}
+
+// decodingReader converts the values of rows read by another reader using a ValueDecoder.
+type decodingReader struct {
+	SqlTableReader
+	sch  schema.Schema
+	dec  ValueDecoder
+	conv *row.SqlRowConverter
+}
+
+var _ SqlTableReader = &decodingReader{}
+
+// NewTableReaderWithDecoder creates a SqlTableReader over the rows of |tbl| which returns rows with the schema |sch|
+// rather than the schema the table's rows were written with, using |dec| to convert each stored value to the type of
+// its column in |sch|. Columns are matched by tag, and stored values with no column in |sch| are discarded. This
+// allows tables written in an older value encoding to be read as rows of their current schema.
+func NewTableReaderWithDecoder(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, dec ValueDecoder, opts ...ReaderOption) (SqlTableReader, error) {
+	rdr, err := NewTableReader(ctx, tbl)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyReaderOptions(&decodingReader{
+		SqlTableReader: rdr,
+		sch:            sch,
+		dec:            dec,
+		conv:           row.NewSqlRowConverter(sch),
+	}, opts)
+}
+
+// GetSchema implements the TableReader interface.
+func (rd *decodingReader) GetSchema() schema.Schema {
+	return rd.sch
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *decodingReader) ReadRow(ctx context.Context) (row.Row, error) {
+	r, err := rd.SqlTableReader.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := rd.sch.GetAllCols()
+	tv := make(row.TaggedValues, cols.Size())
+	_, err = r.IterCols(func(tag uint64, val types.Value) (stop bool, err error) {
+		col, ok := cols.GetByTag(tag)
+		if !ok || types.IsNull(val) {
+			return false, nil
+		}
+
+		tv[tag], err = rd.dec(col, val)
+		return err != nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.IsKeyless(rd.sch) {
+		return row.NewKeylessRow(r.Format(), rd.sch, tv, 1)
+	}
+
+	return row.New(r.Format(), rd.sch, tv)
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *decodingReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.conv.Convert(r)
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *decodingReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
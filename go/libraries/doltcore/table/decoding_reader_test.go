@@ -0,0 +1,112 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestTableReaderWithDecoder(t *testing.T) {
+	ctx := context.Background()
+
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("name", 1, types.StringKind, false),
+		schema.NewColumn("score", 2, types.FloatKind, false),
+		schema.NewColumn("active", 3, types.BoolKind, false),
+	)
+	require.NoError(t, err)
+	sch, err := schema.SchemaFromCols(coll)
+	require.NoError(t, err)
+
+	// the fixture table stores every value as a string, as tables written without a schema did
+	legacySch, err := untyped.UntypeSchema(sch)
+	require.NoError(t, err)
+
+	vrw, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_Default, nil, nil)
+	require.NoError(t, err)
+
+	emptyMap, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	me := emptyMap.Edit()
+	for _, strs := range []map[uint64]string{
+		{0: "1", 1: "one", 2: "1.5", 3: "true"},
+		{0: "2", 1: "two", 3: "false"},
+		{0: "10", 2: "-3"},
+	} {
+		r, err := untyped.NewRowFromTaggedStrings(vrw.Format(), legacySch, strs)
+		require.NoError(t, err)
+		me.Set(r.NomsMapKey(legacySch), r.NomsMapValue(legacySch))
+	}
+	rowData, err := me.Map(ctx)
+	require.NoError(t, err)
+
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, legacySch)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData, emptyMap)
+	require.NoError(t, err)
+
+	t.Run("decode strings", func(t *testing.T) {
+		rdr, err := NewTableReaderWithDecoder(ctx, tbl, sch, DecodeStringValues)
+		require.NoError(t, err)
+		assert.Equal(t, sch, rdr.GetSchema())
+
+		// string keys are ordered lexicographically, so "10" is read before "2"
+		assert.Equal(t, []sql.Row{
+			{int64(1), "one", 1.5, uint64(1)},
+			{int64(10), nil, -3.0, nil},
+			{int64(2), "two", nil, uint64(0)},
+		}, readAllSqlRows(t, rdr))
+	})
+
+	t.Run("decoded rows use the new key encoding", func(t *testing.T) {
+		rdr, err := NewTableReaderWithDecoder(ctx, tbl, sch, DecodeStringValues)
+		require.NoError(t, err)
+
+		r, err := rdr.ReadRow(ctx)
+		require.NoError(t, err)
+		expected, err := row.New(types.Format_Default, sch, row.TaggedValues{
+			0: types.Int(1),
+			1: types.String("one"),
+			2: types.Float(1.5),
+			3: types.Bool(true),
+		})
+		require.NoError(t, err)
+		assert.True(t, row.AreEqual(expected, r, sch))
+	})
+
+	t.Run("decode errors", func(t *testing.T) {
+		rdr, err := NewTableReaderWithDecoder(ctx, tbl, sch, func(col schema.Column, val types.Value) (types.Value, error) {
+			return DecodeStringValues(col, types.Int(0))
+		})
+		require.NoError(t, err)
+
+		_, err = rdr.ReadRow(ctx)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,242 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ErrInvalidDedupLimit is returned by NewDedupReader when the number of row hashes to hold in memory is less than 1.
+var ErrInvalidDedupLimit = errors.New("dedup reader must hold at least one row hash in memory")
+
+// ErrDedupLimitExceeded is returned by a DedupReader without a spill directory when it reads more distinct rows than
+// it can hold in memory.
+var ErrDedupLimitExceeded = errors.New("too many distinct rows to deduplicate in memory")
+
+// DedupReader is a SqlTableReader which drops rows that are exact duplicates of a row read earlier from another
+// reader. Rows are compared on the values of every column, with NULL equal to NULL, by hashing their content. The
+// first copy of each row is returned in the order it was read, and later copies are dropped and counted. Every copy
+// of a keyless row is read individually, so only the first copy is returned.
+//
+// Up to a fixed number of hashes are held in memory. If a spill directory is given, the hashes are written to a
+// sorted file in that directory each time the limit is reached, and later rows are looked up in every file with a
+// binary search, so lookups slow down as the number of files grows. The files are removed by Close.
+type DedupReader struct {
+	SqlTableReader
+	conv     *row.SqlRowConverter
+	maxInMem int
+	spillDir string
+
+	seen    hash.HashSet
+	runs    []hashRun
+	dropped uint64
+}
+
+var _ SqlTableReader = &DedupReader{}
+
+// NewDedupReader creates a DedupReader over the rows of |rdr| which holds up to |maxInMem| row hashes in memory. If
+// |spillDir| is empty, reading more than |maxInMem| distinct rows returns ErrDedupLimitExceeded. Otherwise hashes
+// are spilled to temporary files in |spillDir|. Callers should Close the reader to remove its files.
+func NewDedupReader(rdr SqlTableReader, maxInMem int, spillDir string) (*DedupReader, error) {
+	if maxInMem < 1 {
+		return nil, ErrInvalidDedupLimit
+	}
+
+	return &DedupReader{
+		SqlTableReader: rdr,
+		conv:           row.NewSqlRowConverter(rdr.GetSchema()),
+		maxInMem:       maxInMem,
+		spillDir:       spillDir,
+		seen:           hash.NewHashSet(),
+	}, nil
+}
+
+// Dropped returns the number of duplicate rows that have been dropped so far.
+func (rd *DedupReader) Dropped() uint64 {
+	return rd.dropped
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *DedupReader) ReadRow(ctx context.Context) (row.Row, error) {
+	for {
+		r, err := rd.SqlTableReader.ReadRow(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		tpl, err := taggedValuesTuple(r)
+		if err != nil {
+			return nil, err
+		}
+
+		h, err := tpl.Hash(r.Format())
+		if err != nil {
+			return nil, err
+		}
+
+		dup, err := rd.contains(h)
+		if err != nil {
+			return nil, err
+		} else if dup {
+			rd.dropped++
+			continue
+		}
+
+		if err = rd.insert(h); err != nil {
+			return nil, err
+		}
+
+		return r, nil
+	}
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *DedupReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.conv.Convert(r)
+}
+
+// Close removes any spill files and closes the underlying reader if it can be closed. The first error encountered
+// is returned.
+func (rd *DedupReader) Close(ctx context.Context) error {
+	var firstErr error
+	for _, run := range rd.runs {
+		if err := run.f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err := os.Remove(run.f.Name()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	rd.runs = nil
+	rd.seen = hash.NewHashSet()
+
+	if err := closeReader(ctx, rd.SqlTableReader); err != nil && firstErr == nil {
+		firstErr = err
+	}
+
+	return firstErr
+}
+
+// contains returns whether |h| has been seen, either in memory or in one of the spill files.
+func (rd *DedupReader) contains(h hash.Hash) (bool, error) {
+	if rd.seen.Has(h) {
+		return true, nil
+	}
+
+	for _, run := range rd.runs {
+		found, err := run.contains(h)
+		if err != nil || found {
+			return found, err
+		}
+	}
+
+	return false, nil
+}
+
+// insert records |h| in memory, first spilling the hashes in memory to a new file if the limit has been reached.
+func (rd *DedupReader) insert(h hash.Hash) error {
+	if len(rd.seen) >= rd.maxInMem {
+		if rd.spillDir == "" {
+			return ErrDedupLimitExceeded
+		}
+
+		if err := rd.spill(); err != nil {
+			return err
+		}
+	}
+
+	rd.seen.Insert(h)
+
+	return nil
+}
+
+// spill writes the hashes in memory to a new file in sorted order and clears them from memory.
+func (rd *DedupReader) spill() error {
+	hashes := make(hash.HashSlice, 0, len(rd.seen))
+	for h := range rd.seen {
+		hashes = append(hashes, h)
+	}
+	sort.Sort(hashes)
+
+	f, err := ioutil.TempFile(rd.spillDir, "dedup-*.hashes")
+	if err != nil {
+		return err
+	}
+
+	wr := bufio.NewWriter(f)
+	for _, h := range hashes {
+		if _, err = wr.Write(h[:]); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = wr.Flush()
+	}
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+
+	rd.runs = append(rd.runs, hashRun{f: f, n: len(hashes)})
+	rd.seen = hash.NewHashSet()
+
+	return nil
+}
+
+// hashRun is a spill file holding |n| hashes in sorted order.
+type hashRun struct {
+	f *os.File
+	n int
+}
+
+// contains returns whether the run contains |h|.
+func (run hashRun) contains(h hash.Hash) (bool, error) {
+	var buf hash.Hash
+	var readErr error
+	i := sort.Search(run.n, func(i int) bool {
+		if readErr != nil {
+			return true
+		}
+		if _, readErr = run.f.ReadAt(buf[:], int64(i)*hash.ByteLen); readErr != nil {
+			return true
+		}
+		return !buf.Less(h)
+	})
+	if readErr != nil || i == run.n {
+		return false, readErr
+	}
+
+	if _, err := run.f.ReadAt(buf[:], int64(i)*hash.ByteLen); err != nil {
+		return false, err
+	}
+
+	return buf == h, nil
+}
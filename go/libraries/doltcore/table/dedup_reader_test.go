@@ -0,0 +1,127 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDedupReader(t *testing.T) {
+	ctx := context.Background()
+
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("name", 1, types.StringKind, false),
+	)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(coll)
+
+	newRow := func(id int64, name interface{}) row.Row {
+		tv := row.TaggedValues{0: types.Int(id)}
+		if name != nil {
+			tv[1] = types.String(name.(string))
+		}
+		r, err := row.New(types.Format_Default, sch, tv)
+		require.NoError(t, err)
+		return r
+	}
+
+	// imported rows with duplicates interleaved, including rows that share a key but differ in content
+	rows := []row.Row{
+		newRow(3, "c"),
+		newRow(1, "a"),
+		newRow(3, "c"),
+		newRow(2, nil),
+		newRow(1, "a"),
+		newRow(1, "b"),
+		newRow(2, nil),
+		newRow(3, "c"),
+		newRow(0, "a"),
+	}
+	expected := [][]interface{}{
+		{int64(3), "c"},
+		{int64(1), "a"},
+		{int64(2), nil},
+		{int64(1), "b"},
+		{int64(0), "a"},
+	}
+
+	readAll := func(t *testing.T, rd *DedupReader) [][]interface{} {
+		var actual [][]interface{}
+		for _, r := range readAllSqlRows(t, rd) {
+			actual = append(actual, []interface{}(r))
+		}
+		return actual
+	}
+
+	t.Run("in memory", func(t *testing.T) {
+		rd, err := NewDedupReader(NewInMemTableReader(NewInMemTableWithData(sch, rows)), 100, "")
+		require.NoError(t, err)
+		defer rd.Close(ctx)
+
+		assert.Equal(t, expected, readAll(t, rd))
+		assert.Equal(t, uint64(4), rd.Dropped())
+	})
+
+	t.Run("spilled to disk", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "dedup_test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		rd, err := NewDedupReader(NewInMemTableReader(NewInMemTableWithData(sch, rows)), 2, dir)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, readAll(t, rd))
+		assert.Equal(t, uint64(4), rd.Dropped())
+
+		files, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, files, 2)
+
+		require.NoError(t, rd.Close(ctx))
+		files, err = ioutil.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("limit exceeded without spilling", func(t *testing.T) {
+		rd, err := NewDedupReader(NewInMemTableReader(NewInMemTableWithData(sch, rows)), 2, "")
+		require.NoError(t, err)
+		defer rd.Close(ctx)
+
+		for i := 0; i < 2; i++ {
+			_, err = rd.ReadRow(ctx)
+			require.NoError(t, err)
+		}
+		_, err = rd.ReadRow(ctx)
+		assert.Equal(t, ErrDedupLimitExceeded, err)
+		assert.Equal(t, uint64(1), rd.Dropped())
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		_, err := NewDedupReader(NewInMemTableReader(NewInMemTableWithData(sch, rows)), 0, "")
+		assert.Equal(t, ErrInvalidDedupLimit, err)
+	})
+}
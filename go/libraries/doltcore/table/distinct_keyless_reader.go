@@ -0,0 +1,95 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// ErrNotKeyless is returned when creating a reader that only supports keyless tables for a table with a primary key.
+var ErrNotKeyless = errors.New("table is not keyless")
+
+// DistinctKeylessReader reads each physical row of a keyless table exactly once, rather than once per copy as the
+// readers returned by NewTableReader do. ReadRowWithCardinality returns the number of copies of each row, so
+// consumers that aggregate rows don't need to read every copy. ReadRow and ReadSqlRow return each physical row once,
+// discarding its cardinality.
+type DistinctKeylessReader struct {
+	rdr *keylessTableReader
+}
+
+var _ SqlTableReader = &DistinctKeylessReader{}
+var _ cardinalityReader = &DistinctKeylessReader{}
+
+// NewDistinctKeylessReader creates a DistinctKeylessReader over the rows of |tbl|, which must be keyless. Callers
+// should Close the reader when they are done with it.
+func NewDistinctKeylessReader(ctx context.Context, tbl *doltdb.Table) (*DistinctKeylessReader, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !schema.IsKeyless(sch) {
+		return nil, ErrNotKeyless
+	}
+
+	rdr, err := newKeylessTableReader(ctx, tbl, sch, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DistinctKeylessReader{rdr: rdr.(*keylessTableReader)}, nil
+}
+
+// GetSchema implements the TableReader interface.
+func (rd *DistinctKeylessReader) GetSchema() schema.Schema {
+	return rd.rdr.GetSchema()
+}
+
+// ReadRowWithCardinality reads the next physical row along with the number of copies of it in the table.
+func (rd *DistinctKeylessReader) ReadRowWithCardinality(ctx context.Context) (row.Row, uint64, error) {
+	return rd.rdr.ReadRowWithCardinality(ctx)
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *DistinctKeylessReader) ReadRow(ctx context.Context) (row.Row, error) {
+	r, _, err := rd.rdr.ReadRowWithCardinality(ctx)
+	return r, err
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *DistinctKeylessReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if rd.rdr.conv == nil {
+		rd.rdr.conv = row.NewSqlRowConverter(rd.rdr.sch)
+	}
+
+	return rd.rdr.conv.Convert(r)
+}
+
+// Close releases the reader's map iterator. Reads from a closed reader return ErrReaderClosed.
+func (rd *DistinctKeylessReader) Close(ctx context.Context) error {
+	return rd.rdr.Close(ctx)
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDistinctKeylessReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 0, card: 2},
+		keylessTestRow{c0: 1, c1: 1, card: 3},
+		keylessTestRow{c0: 2, c1: 2, card: 1},
+	)
+
+	t.Run("read with cardinality", func(t *testing.T) {
+		rdr, err := NewDistinctKeylessReader(ctx, tbl)
+		require.NoError(t, err)
+		defer rdr.Close(ctx)
+
+		counts := make(map[int64]uint64)
+		for {
+			r, card, err := rdr.ReadRowWithCardinality(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			c0, ok := r.GetColVal(keylessC0Tag)
+			require.True(t, ok)
+			counts[int64(c0.(types.Int))] = card
+		}
+
+		assert.Equal(t, map[int64]uint64{0: 2, 1: 3, 2: 1}, counts)
+	})
+
+	t.Run("read rows", func(t *testing.T) {
+		rdr, err := NewDistinctKeylessReader(ctx, tbl)
+		require.NoError(t, err)
+		defer rdr.Close(ctx)
+
+		assert.Equal(t, map[int64]uint64{0: 1, 1: 1, 2: 1}, readKeylessMultiset(t, rdr))
+	})
+
+	t.Run("keyed table", func(t *testing.T) {
+		_, err := NewDistinctKeylessReader(ctx, newKeyedTestTable(t, 3))
+		assert.Equal(t, ErrNotKeyless, err)
+	})
+}
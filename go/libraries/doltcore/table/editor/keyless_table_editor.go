@@ -16,12 +16,344 @@ package editor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
+var ErrKeylessRowNotFound = errors.New("cannot delete a row that does not exist in a keyless table")
+
+// KeylessMergeFunc decides the cardinality of a keyless row when updates make distinct rows identical. |existing| is
+// a row whose copies already have the merged contents, or were updated to them earlier in the same set of edits.
+// |updated| is another row, as it was before it was updated, whose copies now have the same contents. |existingCard|
+// and |updatedCard| are the number of copies of each, and the returned value is the cardinality of the merged row.
+type KeylessMergeFunc func(ctx context.Context, existing row.Row, existingCard uint64, updated row.Row, updatedCard uint64) (uint64, error)
+
+// SumCardinalities is the default KeylessMergeFunc, which keeps every copy of both rows.
+func SumCardinalities(_ context.Context, _ row.Row, existingCard uint64, _ row.Row, updatedCard uint64) (uint64, error) {
+	return existingCard + updatedCard, nil
+}
+
+// keylessTableEditor supports making multiple row edits (inserts, updates, deletes) to a keyless table. Rows of a
+// keyless table are identified by a hash of their contents, so edits are accumulated as changes to the number of
+// copies of each distinct row, and applied to the table's row data when the table is requested.
+//
+// This type is thread-safe, and may be used in a multi-threaded environment.
+type keylessTableEditor struct {
+	t    *doltdb.Table
+	tSch schema.Schema
+	name string
+	nbf  *types.NomsBinFormat
+
+	rowData types.Map
+	acc     map[hash.Hash]*keylessEdit
+	merge   KeylessMergeFunc
+
+	mu *sync.Mutex
+}
+
+// keylessEdit is the accumulated change in cardinality of a single keyless row.
+type keylessEdit struct {
+	key types.Tuple
+	// tv is set when the contents of the row are known, which is always the case for inserted rows.
+	tv    row.TaggedValues
+	delta int64
+
+	// sources are the distinct rows that were updated to have the contents of this row, in the order they were first
+	// updated. Their copies are included in |delta|.
+	sources   []*keylessSource
+	sourceIdx map[*keylessEdit]int
+}
+
+// keylessSource is a row whose contents were changed by |card| calls to UpdateRow.
+type keylessSource struct {
+	r    row.Row
+	card uint64
+}
+
+var _ TableEditor = &keylessTableEditor{}
+
 func newKeylessTableEditor(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, name string) (TableEditor, error) {
-	return nil, fmt.Errorf("keylessTableEditor unimplemented")
+	return NewKeylessTableEditor(ctx, tbl, sch, name, nil)
+}
+
+// NewKeylessTableEditor creates a TableEditor for the keyless table |tbl|. When an update makes rows that were
+// distinct identical, |merge| decides the cardinality of the resulting row. If |merge| is nil, SumCardinalities is
+// used.
+func NewKeylessTableEditor(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, name string, merge KeylessMergeFunc) (TableEditor, error) {
+	if merge == nil {
+		merge = SumCardinalities
+	}
+
+	if sch.Indexes().Count() > 0 {
+		return nil, fmt.Errorf("editing keyless table '%s' with secondary indexes is not supported", name)
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keylessTableEditor{
+		t:       tbl,
+		tSch:    sch,
+		name:    name,
+		nbf:     tbl.Format(),
+		rowData: rowData,
+		acc:     make(map[hash.Hash]*keylessEdit),
+		merge:   merge,
+		mu:      &sync.Mutex{},
+	}, nil
+}
+
+// InsertRow adds one copy of |r| to the table.
+func (kte *keylessTableEditor) InsertRow(ctx context.Context, r row.Row) error {
+	kte.mu.Lock()
+	defer kte.mu.Unlock()
+
+	return kte.addRow(ctx, r, 1)
+}
+
+// UpdateRow replaces one copy of |old| with one copy of |new|.
+func (kte *keylessTableEditor) UpdateRow(ctx context.Context, old, new row.Row) error {
+	kte.mu.Lock()
+	defer kte.mu.Unlock()
+
+	oldEdit, err := kte.addRow(ctx, old, -1)
+	if err != nil {
+		return err
+	}
+
+	newEdit, err := kte.addRow(ctx, new, 1)
+	if err != nil {
+		return err
+	}
+
+	if oldEdit != newEdit {
+		newEdit.addSource(oldEdit, old)
+	}
+
+	return nil
+}
+
+// DeleteKey removes one copy of the row with the keyless row id |key|.
+func (kte *keylessTableEditor) DeleteKey(ctx context.Context, key types.Tuple) error {
+	kte.mu.Lock()
+	defer kte.mu.Unlock()
+
+	_, err := kte.addDelta(key, nil, -1)
+	return err
+}
+
+func (kte *keylessTableEditor) addRow(ctx context.Context, r row.Row, delta int64) (*keylessEdit, error) {
+	tv, err := row.GetTaggedVals(r)
+	if err != nil {
+		return nil, err
+	}
+
+	kr, err := row.NewKeylessRow(kte.nbf, kte.tSch, tv, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := kr.NomsMapKey(kte.tSch).Value(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return kte.addDelta(key.(types.Tuple), tv, delta)
+}
+
+func (kte *keylessTableEditor) addDelta(key types.Tuple, tv row.TaggedValues, delta int64) (*keylessEdit, error) {
+	h, err := key.Hash(kte.nbf)
+	if err != nil {
+		return nil, err
+	}
+
+	edit, ok := kte.acc[h]
+	if !ok {
+		edit = &keylessEdit{key: key}
+		kte.acc[h] = edit
+	}
+
+	if edit.tv == nil {
+		edit.tv = tv
+	}
+	edit.delta += delta
+
+	return edit, nil
+}
+
+// addSource records that a copy of |r|, whose accumulated edit is |from|, was updated to have the contents of this row.
+func (edit *keylessEdit) addSource(from *keylessEdit, r row.Row) {
+	if edit.sourceIdx == nil {
+		edit.sourceIdx = make(map[*keylessEdit]int)
+	}
+
+	if idx, ok := edit.sourceIdx[from]; ok {
+		edit.sources[idx].card++
+		return
+	}
+
+	edit.sourceIdx[from] = len(edit.sources)
+	edit.sources = append(edit.sources, &keylessSource{r: r, card: 1})
+}
+
+// GetAutoIncrementValue implements TableEditor. Keyless tables do not have auto increment columns.
+func (kte *keylessTableEditor) GetAutoIncrementValue() types.Value {
+	return nil
+}
+
+// SetAutoIncrementValue implements TableEditor. Keyless tables do not have auto increment columns.
+func (kte *keylessTableEditor) SetAutoIncrementValue(v types.Value) error {
+	return fmt.Errorf("keyless table '%s' does not have an auto increment column", kte.name)
+}
+
+// Table applies all accumulated edits and returns the updated table. If the edits cannot be applied they are
+// discarded.
+func (kte *keylessTableEditor) Table(ctx context.Context) (*doltdb.Table, error) {
+	kte.mu.Lock()
+	defer kte.mu.Unlock()
+
+	if len(kte.acc) == 0 {
+		return kte.t, nil
+	}
+
+	defer func() {
+		kte.acc = make(map[hash.Hash]*keylessEdit)
+	}()
+
+	ed := kte.rowData.Edit()
+	for _, edit := range kte.acc {
+		if edit.delta == 0 && len(edit.sources) == 0 {
+			continue
+		}
+
+		var card int64
+		tv := edit.tv
+
+		val, ok, err := kte.rowData.MaybeGet(ctx, edit.key)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			r, c, err := row.KeylessRowsFromTuples(edit.key, val.(types.Tuple))
+			if err != nil {
+				return nil, err
+			}
+
+			card = int64(c)
+			if tv == nil {
+				tv, err = row.GetTaggedVals(r)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		merged, err := kte.mergeSources(ctx, tv, card, edit)
+		if err != nil {
+			return nil, err
+		} else if merged == 0 {
+			ed.Remove(edit.key)
+			continue
+		}
+
+		r, err := row.NewKeylessRow(kte.nbf, kte.tSch, tv, merged)
+		if err != nil {
+			return nil, err
+		}
+
+		ed.Set(r.NomsMapKey(kte.tSch), r.NomsMapValue(kte.tSch))
+	}
+
+	updated, err := ed.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err := kte.t.UpdateRows(ctx, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	kte.t = tbl
+	kte.rowData = updated
+
+	return tbl, nil
+}
+
+// mergeSources returns the cardinality of the row with the contents |tv| after applying |edit| to the |stored| copies
+// of it. Copies of other rows that were updated to have these contents are merged with the copies that already had
+// them using the editor's KeylessMergeFunc.
+func (kte *keylessTableEditor) mergeSources(ctx context.Context, tv row.TaggedValues, stored int64, edit *keylessEdit) (uint64, error) {
+	card := stored + edit.delta
+	for _, src := range edit.sources {
+		card -= int64(src.card)
+	}
+
+	// deletes of copies that were updated to this row are taken from the most recently updated rows
+	for i := len(edit.sources) - 1; card < 0 && i >= 0; i-- {
+		src := edit.sources[i]
+		n := uint64(-card)
+		if n > src.card {
+			n = src.card
+		}
+		src.card -= n
+		card += int64(n)
+	}
+	if card < 0 {
+		return 0, ErrKeylessRowNotFound
+	}
+
+	var existing row.Row
+	if card > 0 {
+		var err error
+		existing, err = row.NewKeylessRow(kte.nbf, kte.tSch, tv, uint64(card))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	merged := uint64(card)
+	for _, src := range edit.sources {
+		if src.card == 0 {
+			continue
+		} else if existing == nil {
+			existing, merged = src.r, src.card
+			continue
+		}
+
+		var err error
+		merged, err = kte.merge(ctx, existing, merged, src.r, src.card)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return merged, nil
+}
+
+func (kte *keylessTableEditor) Schema() schema.Schema {
+	return kte.tSch
+}
+
+func (kte *keylessTableEditor) Name() string {
+	return kte.name
+}
+
+func (kte *keylessTableEditor) Format() *types.NomsBinFormat {
+	return kte.nbf
+}
+
+// Close implements TableEditor. Edits that have not been applied by a call to Table are discarded.
+func (kte *keylessTableEditor) Close() error {
+	return nil
 }
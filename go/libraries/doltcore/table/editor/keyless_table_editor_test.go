@@ -0,0 +1,222 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package editor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// newKeylessEditorTestTable must be called with schema.FeatureFlagKeylessSchema enabled. It returns an empty keyless
+// table with the int columns c0 and c1.
+func newKeylessEditorTestTable(t *testing.T) (schema.Schema, *doltdb.Table) {
+	ctx := context.Background()
+	db, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_7_18, nil, nil)
+	require.NoError(t, err)
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn("c0", 0, types.IntKind, false),
+		schema.NewColumn("c1", 1, types.IntKind, false))
+	require.NoError(t, err)
+	tableSch, err := schema.SchemaFromCols(colColl)
+	require.NoError(t, err)
+	require.True(t, schema.IsKeyless(tableSch))
+	tableSchVal, err := encoding.MarshalSchemaAsNomsValue(ctx, db, tableSch)
+	require.NoError(t, err)
+	emptyMap, err := types.NewMap(ctx, db)
+	require.NoError(t, err)
+	table, err := doltdb.NewTable(ctx, db, tableSchVal, emptyMap, emptyMap)
+	require.NoError(t, err)
+
+	return tableSch, table
+}
+
+func newKeylessEditorTestRow(t *testing.T, sch schema.Schema, c0, c1 int) row.Row {
+	r, err := row.NewKeylessRow(types.Format_7_18, sch, row.TaggedValues{
+		0: types.Int(c0),
+		1: types.Int(c1),
+	}, 1)
+	require.NoError(t, err)
+	return r
+}
+
+// keylessCardinalities returns the cardinality of each row of |tbl| by its c0 value.
+func keylessCardinalities(t *testing.T, tbl *doltdb.Table) map[int]uint64 {
+	ctx := context.Background()
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+	cards := make(map[int]uint64)
+	err = rowData.IterAll(ctx, func(key, value types.Value) error {
+		r, card, err := row.KeylessRowsFromTuples(key.(types.Tuple), value.(types.Tuple))
+		require.NoError(t, err)
+		c0, _ := r.GetColVal(0)
+		cards[int(c0.(types.Int))] = card
+		return nil
+	})
+	require.NoError(t, err)
+	return cards
+}
+
+func TestKeylessTableEditor(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	tableSch, table := newKeylessEditorTestTable(t)
+
+	tableEditor, err := NewTableEditor(ctx, table, tableSch, tableName)
+	require.NoError(t, err)
+
+	newRow := func(c0, c1 int) row.Row {
+		return newKeylessEditorTestRow(t, tableSch, c0, c1)
+	}
+	keyOf := func(r row.Row) types.Tuple {
+		key, err := r.NomsMapKey(tableSch).Value(ctx)
+		require.NoError(t, err)
+		return key.(types.Tuple)
+	}
+
+	// duplicate rows are stored once along with their cardinality
+	require.NoError(t, tableEditor.InsertRow(ctx, newRow(1, 1)))
+	require.NoError(t, tableEditor.InsertRow(ctx, newRow(1, 1)))
+	require.NoError(t, tableEditor.InsertRow(ctx, newRow(1, 1)))
+	require.NoError(t, tableEditor.InsertRow(ctx, newRow(2, 2)))
+	tbl, err := tableEditor.Table(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]uint64{1: 3, 2: 1}, keylessCardinalities(t, tbl))
+
+	// updating a row moves a single copy of it
+	require.NoError(t, tableEditor.UpdateRow(ctx, newRow(1, 1), newRow(3, 3)))
+	tbl, err = tableEditor.Table(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]uint64{1: 2, 2: 1, 3: 1}, keylessCardinalities(t, tbl))
+
+	// updating a row to an existing row increments its cardinality, and removes the old row when no copies remain
+	require.NoError(t, tableEditor.UpdateRow(ctx, newRow(2, 2), newRow(3, 3)))
+	require.NoError(t, tableEditor.DeleteKey(ctx, keyOf(newRow(1, 1))))
+	tbl, err = tableEditor.Table(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]uint64{1: 1, 3: 2}, keylessCardinalities(t, tbl))
+
+	// deleting a copy that was just updated into a row is not a collision
+	require.NoError(t, tableEditor.UpdateRow(ctx, newRow(1, 1), newRow(4, 4)))
+	require.NoError(t, tableEditor.DeleteKey(ctx, keyOf(newRow(4, 4))))
+	tbl, err = tableEditor.Table(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]uint64{3: 2}, keylessCardinalities(t, tbl))
+
+	// deleting more copies than exist is an error, and the edits are discarded
+	require.NoError(t, tableEditor.DeleteKey(ctx, keyOf(newRow(3, 3))))
+	require.NoError(t, tableEditor.DeleteKey(ctx, keyOf(newRow(3, 3))))
+	require.NoError(t, tableEditor.DeleteKey(ctx, keyOf(newRow(3, 3))))
+	_, err = tableEditor.Table(ctx)
+	assert.Equal(t, ErrKeylessRowNotFound, err)
+	tbl, err = tableEditor.Table(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]uint64{3: 2}, keylessCardinalities(t, tbl))
+}
+
+func TestKeylessTableEditorMerge(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	tableSch, table := newKeylessEditorTestTable(t)
+
+	newRow := func(c0, c1 int) row.Row {
+		return newKeylessEditorTestRow(t, tableSch, c0, c1)
+	}
+
+	// (1, 1) x 1 and (2, 2) x 2 are both updated to (3, 3), one copy at a time as the engine does
+	collapse := func(tableEditor TableEditor) *doltdb.Table {
+		require.NoError(t, tableEditor.InsertRow(ctx, newRow(1, 1)))
+		require.NoError(t, tableEditor.InsertRow(ctx, newRow(2, 2)))
+		require.NoError(t, tableEditor.InsertRow(ctx, newRow(2, 2)))
+		_, err := tableEditor.Table(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, tableEditor.UpdateRow(ctx, newRow(1, 1), newRow(3, 1)))
+		require.NoError(t, tableEditor.UpdateRow(ctx, newRow(2, 2), newRow(3, 1)))
+		require.NoError(t, tableEditor.UpdateRow(ctx, newRow(2, 2), newRow(3, 1)))
+		tbl, err := tableEditor.Table(ctx)
+		require.NoError(t, err)
+		return tbl
+	}
+
+	t.Run("sums cardinalities by default", func(t *testing.T) {
+		tableEditor, err := NewTableEditor(ctx, table, tableSch, tableName)
+		require.NoError(t, err)
+
+		tbl := collapse(tableEditor)
+		assert.Equal(t, map[int]uint64{3: 3}, keylessCardinalities(t, tbl))
+	})
+
+	t.Run("merge func", func(t *testing.T) {
+		type mergeArgs struct {
+			existing, updated         int
+			existingCard, updatedCard uint64
+		}
+		var calls []mergeArgs
+		merge := func(ctx context.Context, existing row.Row, existingCard uint64, updated row.Row, updatedCard uint64) (uint64, error) {
+			e, _ := existing.GetColVal(1)
+			u, _ := updated.GetColVal(1)
+			calls = append(calls, mergeArgs{int(e.(types.Int)), int(u.(types.Int)), existingCard, updatedCard})
+			if existingCard > updatedCard {
+				return existingCard, nil
+			}
+			return updatedCard, nil
+		}
+
+		tableEditor, err := NewKeylessTableEditor(ctx, table, tableSch, tableName, merge)
+		require.NoError(t, err)
+
+		tbl := collapse(tableEditor)
+		assert.Equal(t, map[int]uint64{3: 2}, keylessCardinalities(t, tbl))
+		assert.Equal(t, []mergeArgs{{existing: 1, updated: 2, existingCard: 1, updatedCard: 2}}, calls)
+	})
+
+	t.Run("merge with a row that already existed", func(t *testing.T) {
+		var existingCards []uint64
+		merge := func(ctx context.Context, existing row.Row, existingCard uint64, updated row.Row, updatedCard uint64) (uint64, error) {
+			existingCards = append(existingCards, existingCard)
+			return SumCardinalities(ctx, existing, existingCard, updated, updatedCard)
+		}
+
+		tableEditor, err := NewKeylessTableEditor(ctx, table, tableSch, tableName, merge)
+		require.NoError(t, err)
+
+		require.NoError(t, tableEditor.InsertRow(ctx, newRow(5, 5)))
+		require.NoError(t, tableEditor.InsertRow(ctx, newRow(6, 6)))
+		_, err = tableEditor.Table(ctx)
+		require.NoError(t, err)
+
+		// updating a row to its own contents is not a merge
+		require.NoError(t, tableEditor.UpdateRow(ctx, newRow(5, 5), newRow(5, 5)))
+		require.NoError(t, tableEditor.UpdateRow(ctx, newRow(6, 6), newRow(5, 5)))
+		tbl, err := tableEditor.Table(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[int]uint64{5: 2}, keylessCardinalities(t, tbl))
+		assert.Equal(t, []uint64{1}, existingCards)
+	})
+}
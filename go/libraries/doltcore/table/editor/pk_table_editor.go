@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"sync"
 
@@ -420,6 +421,20 @@ func (te *pkTableEditor) UpdateRow(ctx context.Context, dOldRow row.Row, dNewRow
 
 	te.tea.affectedKeys[newHash] = dNewKeyVal
 
+	if te.hasAutoInc {
+		// an update to a value at or past the counter advances it, so later inserts don't collide with the row
+		updateVal, ok := dNewRow.GetColVal(te.autoIncCol.Tag)
+		if ok && !types.IsNull(updateVal) {
+			less, err := updateVal.Less(te.nbf, te.autoIncVal)
+			if err != nil {
+				return err
+			}
+			if !less {
+				te.autoIncVal = types.Increment(types.Round(updateVal))
+			}
+		}
+	}
+
 	te.tea.ed.AddEdit(dNewKeyVal, dNewRow.NomsMapValue(te.tSch))
 	te.tea.opCount++
 	return nil
@@ -520,7 +535,8 @@ func (te *pkTableEditor) flushEditAccumulator(ctx context.Context, teaInterface
 	tea := teaInterface.(*tableEditAccumulator)
 	defer tea.ed.Close()
 
-	// For all added keys, check for and report a collision
+	// For all added keys, check for collisions and report all of them together
+	var collisions []types.Value
 	for keyHash, addedKey := range tea.addedKeys {
 		if _, ok := tea.removedKeys[keyHash]; !ok {
 			_, rowExists, err := te.rowData.MaybeGet(ctx, addedKey)
@@ -528,14 +544,13 @@ func (te *pkTableEditor) flushEditAccumulator(ctx context.Context, teaInterface
 				return errhand.BuildDError("failed to read table").AddCause(err).Build()
 			}
 			if rowExists {
-				keyStr, err := formatKey(ctx, addedKey)
-				if err != nil {
-					return err
-				}
-				return fmt.Errorf(ErrDuplicatePrimaryKeyFmt, keyStr)
+				collisions = append(collisions, addedKey)
 			}
 		}
 	}
+	if len(collisions) > 0 {
+		return duplicatePrimaryKeysError(ctx, te.nbf, collisions)
+	}
 	// For all removed keys, remove the map entries that weren't added elsewhere by other updates
 	for keyHash, removedKey := range tea.removedKeys {
 		if _, ok := tea.addedKeys[keyHash]; !ok {
@@ -571,6 +586,33 @@ func (te *pkTableEditor) flushEditAccumulator(ctx context.Context, teaInterface
 	return nil
 }
 
+// duplicatePrimaryKeysError returns an error listing every key in |keys|, in key order. The message for a single key
+// is the same as the message for a duplicate key found on insert.
+func duplicatePrimaryKeysError(ctx context.Context, nbf *types.NomsBinFormat, keys []types.Value) error {
+	var sortErr error
+	sort.Slice(keys, func(i, j int) bool {
+		less, err := keys[i].Less(nbf, keys[j])
+		if err != nil && sortErr == nil {
+			sortErr = err
+		}
+		return less
+	})
+	if sortErr != nil {
+		return sortErr
+	}
+
+	keyStrs := make([]string, len(keys))
+	for i, key := range keys {
+		keyStr, err := formatKey(ctx, key)
+		if err != nil {
+			return err
+		}
+		keyStrs[i] = keyStr
+	}
+
+	return fmt.Errorf(ErrDuplicatePrimaryKeyFmt, strings.Join(keyStrs, ", "))
+}
+
 // formatKey returns a comma-separated string representation of the key given.
 func formatKey(ctx context.Context, key types.Value) (string, error) {
 	tuple, ok := key.(types.Tuple)
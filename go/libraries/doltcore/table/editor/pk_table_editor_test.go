@@ -384,3 +384,65 @@ func TestTableEditorDuplicateKeyHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestTableEditorReportsAllDuplicateKeys(t *testing.T) {
+	format := types.Format_7_18
+	db, err := dbfactory.MemFactory{}.CreateDB(context.Background(), format, nil, nil)
+	require.NoError(t, err)
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true),
+		schema.NewColumn("v1", 1, types.IntKind, false))
+	require.NoError(t, err)
+	tableSch, err := schema.SchemaFromCols(colColl)
+	require.NoError(t, err)
+	tableSchVal, err := encoding.MarshalSchemaAsNomsValue(context.Background(), db, tableSch)
+	require.NoError(t, err)
+	emptyMap, err := types.NewMap(context.Background(), db)
+	require.NoError(t, err)
+	table, err := doltdb.NewTable(context.Background(), db, tableSchVal, emptyMap, emptyMap)
+	require.NoError(t, err)
+
+	tableEditor, err := newPkTableEditor(context.Background(), table, tableSch, tableName)
+	require.NoError(t, err)
+
+	newRow := func(pk, v1 int) row.Row {
+		dRow, err := row.New(format, tableSch, row.TaggedValues{
+			0: types.Int(pk),
+			1: types.Int(v1),
+		})
+		require.NoError(t, err)
+		return dRow
+	}
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, tableEditor.InsertRow(context.Background(), newRow(i, i)))
+	}
+	_, err = tableEditor.Table(context.Background())
+	require.NoError(t, err)
+
+	// a single collision keeps the original message
+	require.NoError(t, tableEditor.UpdateRow(context.Background(), newRow(0, 0), newRow(4, 0)))
+	_, err = tableEditor.Table(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "duplicate primary key given: (4)", err.Error())
+
+	// every collision is reported, in key order, and none of the edits are applied
+	require.NoError(t, tableEditor.UpdateRow(context.Background(), newRow(0, 0), newRow(3, 0)))
+	require.NoError(t, tableEditor.InsertRow(context.Background(), newRow(2, 2)))
+	require.NoError(t, tableEditor.InsertRow(context.Background(), newRow(1, 1)))
+	require.NoError(t, tableEditor.InsertRow(context.Background(), newRow(7, 7)))
+	_, err = tableEditor.Table(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "duplicate primary key given: (1), (2), (3)", err.Error())
+
+	newTable, err := tableEditor.Table(context.Background())
+	require.NoError(t, err)
+	newTableData, err := newTable.GetRowData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), newTableData.Len())
+	key, err := newRow(7, 7).NomsMapKey(tableSch).Value(context.Background())
+	require.NoError(t, err)
+	_, ok, err := newTableData.MaybeGet(context.Background(), key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+)
+
+// RowPredicate returns true for rows that should be returned by a FilteredReader.
+type RowPredicate func(r row.Row) (bool, error)
+
+// FilteredReader is a SqlTableReader which only returns the rows of an underlying reader that match a RowPredicate.
+// Each copy of a keyless row is tested separately.
+type FilteredReader struct {
+	SqlTableReader
+	pred RowPredicate
+}
+
+var _ SqlTableReader = &FilteredReader{}
+
+// NewFilteredReader creates a FilteredReader returning the rows of |rdr| for which |pred| returns true.
+func NewFilteredReader(rdr SqlTableReader, pred RowPredicate) *FilteredReader {
+	return &FilteredReader{SqlTableReader: rdr, pred: pred}
+}
+
+// ReadRow implements the TableReader interface. An error returned by the predicate is returned as a read error.
+func (rd *FilteredReader) ReadRow(ctx context.Context) (row.Row, error) {
+	for {
+		r, err := rd.SqlTableReader.ReadRow(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err := rd.pred(r)
+		if err != nil {
+			return nil, err
+		} else if ok {
+			return r, nil
+		}
+	}
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *FilteredReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return row.DoltRowToSqlRow(r, rd.GetSchema())
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *FilteredReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestFilteredReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 0, card: 1},
+		keylessTestRow{c0: 1, c1: 1, card: 2},
+		keylessTestRow{c0: 2, c1: 2, card: 3},
+		keylessTestRow{c0: 3, c1: 3, card: 4},
+		keylessTestRow{c0: 4, c1: 4, card: 5},
+	)
+
+	var calls int
+	isEven := func(r row.Row) (bool, error) {
+		calls++
+		c0, ok := r.GetColVal(keylessC0Tag)
+		if !ok {
+			return false, errors.New("missing c0")
+		}
+		return int64(c0.(types.Int))%2 == 0, nil
+	}
+
+	t.Run("read rows", func(t *testing.T) {
+		calls = 0
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+
+		frdr := NewFilteredReader(rdr, isEven)
+		assert.Equal(t, rdr.GetSchema(), frdr.GetSchema())
+		assert.Equal(t, map[int64]uint64{0: 1, 2: 3, 4: 5}, readKeylessMultiset(t, frdr))
+		// every copy of a keyless row is tested
+		assert.Equal(t, 15, calls)
+	})
+
+	t.Run("read sql rows", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		frdr := NewFilteredReader(rdr, isEven)
+
+		var count int
+		for {
+			r, err := frdr.ReadSqlRow(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(0), r[0].(int64)%2)
+			count++
+		}
+		assert.Equal(t, 9, count)
+	})
+
+	t.Run("predicate error", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+
+		expectedErr := errors.New("predicate failed")
+		frdr := NewFilteredReader(rdr, func(r row.Row) (bool, error) {
+			return false, expectedErr
+		})
+
+		_, err = frdr.ReadRow(ctx)
+		assert.Equal(t, expectedErr, err)
+		_, err = frdr.ReadSqlRow(ctx)
+		assert.Equal(t, expectedErr, err)
+	})
+}
@@ -0,0 +1,187 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// NewHistoryReader creates a SqlTableReader returning the union of the rows of the table named |tableName| at each
+// commit in the range |from|..|to| of |ddb|: the commits reachable from |to| that are not reachable from |from|, as
+// with `git log from..to`. If |from| is empty, every commit reachable from |to| is read. Commits are read oldest
+// first, in topological order. Rows are conformed to |targetSch| using a SchemaConformingReader, and a non-nullable
+// string column named |hashCol| with the tag |hashTag| is added after the columns of |targetSch|, holding the hash of
+// the commit each row was read from. Commits at which the table does not exist are skipped, and an error wrapping
+// doltdb.ErrTableNotFound is returned if it exists at none of them. An error is returned if the type of a column at
+// one of the commits does not fit in its type in |targetSch|.
+func NewHistoryReader(ctx context.Context, ddb *doltdb.DoltDB, from, to hash.Hash, tableName string, targetSch schema.Schema, hashCol string, hashTag uint64) (SqlTableReader, error) {
+	cols := targetSch.GetAllCols()
+	if _, ok := cols.GetByNameCaseInsensitive(hashCol); ok {
+		return nil, fmt.Errorf("cannot add commit hash column '%s', a column with that name already exists", hashCol)
+	}
+	if _, ok := cols.GetByTag(hashTag); ok {
+		return nil, fmt.Errorf("cannot add commit hash column '%s', a column with tag %d already exists", hashCol, hashTag)
+	}
+
+	allCols, err := cols.Append(schema.NewColumn(hashCol, hashTag, types.StringKind, false, schema.NotNullConstraint{}))
+	if err != nil {
+		return nil, err
+	}
+
+	sch, err := schema.SchemaFromCols(allCols)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*doltdb.Commit
+	if from.IsEmpty() {
+		commits, err = commitwalk.GetTopologicalOrderCommits(ctx, ddb, to)
+	} else {
+		commits, err = commitwalk.GetDotDotRevisions(ctx, ddb, to, ddb, from, -1)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// commits are walked newest first
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+
+	readers := make([]SqlTableReader, 0, len(commits))
+	closeAll := func() {
+		for _, rdr := range readers {
+			_ = closeReader(ctx, rdr)
+		}
+	}
+
+	for _, cm := range commits {
+		rdr, err := newHistoryReaderAtCommit(ctx, cm, tableName, targetSch, sch, hashTag)
+		if errors.Is(err, doltdb.ErrTableNotFound) {
+			continue
+		} else if err != nil {
+			closeAll()
+			return nil, err
+		}
+
+		readers = append(readers, rdr)
+	}
+
+	if len(readers) == 0 {
+		return nil, fmt.Errorf("%w: '%s' does not exist at any commit in the range", doltdb.ErrTableNotFound, tableName)
+	}
+
+	return NewMultiTableReader(readers)
+}
+
+func newHistoryReaderAtCommit(ctx context.Context, cm *doltdb.Commit, tableName string, targetSch, sch schema.Schema, hashTag uint64) (SqlTableReader, error) {
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	rdr, err := NewTableReaderAtCommit(ctx, cm, tableName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	srcCols := rdr.GetSchema().GetAllCols()
+	err = targetSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		srcCol, ok := srcCols.GetByTag(tag)
+		if ok && !typeFits(srcCol.TypeInfo, col.TypeInfo) {
+			return true, fmt.Errorf("column '%s' has type %s at commit %s, which does not fit in type %s",
+				col.Name, srcCol.TypeInfo.String(), h.String(), col.TypeInfo.String())
+		}
+		return false, nil
+	})
+	if err != nil {
+		_ = closeReader(ctx, rdr)
+		return nil, err
+	}
+
+	crdr, err := NewSchemaConformingReader(rdr, targetSch)
+	if err != nil {
+		_ = closeReader(ctx, rdr)
+		return nil, err
+	}
+
+	return &commitHashReader{
+		SqlTableReader: crdr,
+		sch:            sch,
+		tag:            hashTag,
+		hash:           types.String(h.String()),
+		conv:           row.NewSqlRowConverter(sch),
+	}, nil
+}
+
+// commitHashReader adds a column holding the hash of the commit its rows were read from to the rows of a
+// SchemaConformingReader.
+type commitHashReader struct {
+	SqlTableReader
+	sch  schema.Schema
+	tag  uint64
+	hash types.String
+	conv *row.SqlRowConverter
+}
+
+// GetSchema returns the target schema with the commit hash column added.
+func (rd *commitHashReader) GetSchema() schema.Schema {
+	return rd.sch
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *commitHashReader) ReadRow(ctx context.Context) (row.Row, error) {
+	r, err := rd.SqlTableReader.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tv, err := row.GetTaggedVals(r)
+	if err != nil {
+		return nil, err
+	}
+	tv[rd.tag] = rd.hash
+
+	if schema.IsKeyless(rd.sch) {
+		return row.NewKeylessRow(r.Format(), rd.sch, tv, 1)
+	}
+	return row.New(r.Format(), rd.sch, tv)
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *commitHashReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.conv.Convert(r)
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *commitHashReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
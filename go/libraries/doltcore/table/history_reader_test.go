@@ -0,0 +1,196 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const historyHashTag = 100
+
+func TestHistoryReader(t *testing.T) {
+	ctx := context.Background()
+
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_Default, doltdb.InMemDoltDB)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	commitRoot := func(root *doltdb.RootValue, msg string) *doltdb.Commit {
+		valHash, err := ddb.WriteRootValue(ctx, root)
+		require.NoError(t, err)
+		meta, err := doltdb.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", msg)
+		require.NoError(t, err)
+		cm, err := ddb.Commit(ctx, valHash, ref.NewBranchRef("master"), meta)
+		require.NoError(t, err)
+		return cm
+	}
+
+	cs, err := doltdb.NewCommitSpec("master")
+	require.NoError(t, err)
+	initial, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	root, err := initial.GetRootValue()
+	require.NoError(t, err)
+
+	// the first commit has the columns pk and val, and the second adds the column c2
+	root, err = root.PutTable(ctx, "t", newKeyedTestTableWithVRW(t, ddb.ValueReadWriter(), 2))
+	require.NoError(t, err)
+	first := commitRoot(root, "create t")
+
+	c2, err := schema.NewColumnWithTypeInfo("c2", 2, typeinfo.Int64Type, false, "", false, "")
+	require.NoError(t, err)
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("val", 1, types.IntKind, false),
+		c2,
+	)
+	require.NoError(t, err)
+	targetSch, err := schema.SchemaFromCols(coll)
+	require.NoError(t, err)
+
+	root, err = root.PutTable(ctx, "t", newHistoryTestTable(t, ddb.ValueReadWriter(), targetSch,
+		row.TaggedValues{0: types.Int(0), 1: types.Int(0), 2: types.Int(100)},
+		row.TaggedValues{0: types.Int(1), 1: types.Int(11)},
+	))
+	require.NoError(t, err)
+	second := commitRoot(root, "add c2")
+
+	root, err = root.PutTable(ctx, "t", newHistoryTestTable(t, ddb.ValueReadWriter(), targetSch,
+		row.TaggedValues{0: types.Int(1), 1: types.Int(11), 2: types.Int(101)},
+		row.TaggedValues{0: types.Int(2), 1: types.Int(22), 2: types.Int(102)},
+	))
+	require.NoError(t, err)
+	third := commitRoot(root, "update t")
+
+	hashOf := func(cm *doltdb.Commit) hash.Hash {
+		h, err := cm.HashOf()
+		require.NoError(t, err)
+		return h
+	}
+
+	t.Run("union across a schema change", func(t *testing.T) {
+		// the range initial..third holds first, second and third, and c2 is added by second
+		rdr, err := NewHistoryReader(ctx, ddb, hashOf(initial), hashOf(third), "t", targetSch, "commit_hash", historyHashTag)
+		require.NoError(t, err)
+		defer rdr.Close(ctx)
+
+		cols := rdr.GetSchema().GetAllCols()
+		require.Equal(t, 4, cols.Size())
+		hashCol, ok := cols.GetByTag(historyHashTag)
+		require.True(t, ok)
+		assert.Equal(t, "commit_hash", hashCol.Name)
+
+		expected := []sql.Row{
+			{int64(0), int64(0), nil, hashOf(first).String()},
+			{int64(1), int64(10), nil, hashOf(first).String()},
+			{int64(0), int64(0), int64(100), hashOf(second).String()},
+			{int64(1), int64(11), nil, hashOf(second).String()},
+			{int64(1), int64(11), int64(101), hashOf(third).String()},
+			{int64(2), int64(22), int64(102), hashOf(third).String()},
+		}
+		assert.Equal(t, expected, readAllSqlRows(t, rdr))
+	})
+
+	t.Run("read rows", func(t *testing.T) {
+		rdr, err := NewHistoryReader(ctx, ddb, hashOf(first), hashOf(third), "t", targetSch, "commit_hash", historyHashTag)
+		require.NoError(t, err)
+		defer rdr.Close(ctx)
+
+		var hashes []string
+		for {
+			r, err := rdr.ReadRow(ctx)
+			if err != nil {
+				break
+			}
+			val, ok := r.GetColVal(historyHashTag)
+			require.True(t, ok)
+			hashes = append(hashes, string(val.(types.String)))
+		}
+		// the range excludes |first|
+		sec, th := hashOf(second).String(), hashOf(third).String()
+		assert.Equal(t, []string{sec, sec, th, th}, hashes)
+	})
+
+	t.Run("commits without the table are skipped", func(t *testing.T) {
+		// an empty start reads every ancestor, including the initial commit, which has no tables
+		rdr, err := NewHistoryReader(ctx, ddb, hash.Hash{}, hashOf(second), "t", targetSch, "commit_hash", historyHashTag)
+		require.NoError(t, err)
+		defer rdr.Close(ctx)
+		assert.Len(t, readAllSqlRows(t, rdr), 4)
+
+		_, err = NewHistoryReader(ctx, ddb, hash.Hash{}, hashOf(initial), "t", targetSch, "commit_hash", historyHashTag)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, doltdb.ErrTableNotFound))
+	})
+
+	t.Run("column type does not fit", func(t *testing.T) {
+		val, err := schema.NewColumnWithTypeInfo("val", 1, typeinfo.Int8Type, false, "", false, "")
+		require.NoError(t, err)
+		coll, err := schema.NewColCollection(
+			schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{}),
+			val,
+		)
+		require.NoError(t, err)
+		narrowSch, err := schema.SchemaFromCols(coll)
+		require.NoError(t, err)
+
+		_, err = NewHistoryReader(ctx, ddb, hashOf(initial), hashOf(first), "t", narrowSch, "commit_hash", historyHashTag)
+		assert.Error(t, err)
+	})
+
+	t.Run("hash column conflicts", func(t *testing.T) {
+		_, err := NewHistoryReader(ctx, ddb, hashOf(initial), hashOf(first), "t", targetSch, "VAL", historyHashTag)
+		assert.Error(t, err)
+		_, err = NewHistoryReader(ctx, ddb, hashOf(initial), hashOf(first), "t", targetSch, "commit_hash", 2)
+		assert.Error(t, err)
+	})
+}
+
+func newHistoryTestTable(t *testing.T, vrw types.ValueReadWriter, sch schema.Schema, rows ...row.TaggedValues) *doltdb.Table {
+	ctx := context.Background()
+
+	emptyMap, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	me := emptyMap.Edit()
+	for _, tv := range rows {
+		r, err := row.New(vrw.Format(), sch, tv)
+		require.NoError(t, err)
+		me.Set(r.NomsMapKey(sch), r.NomsMapValue(sch))
+	}
+	rowData, err := me.Map(ctx)
+	require.NoError(t, err)
+
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData, emptyMap)
+	require.NoError(t, err)
+
+	return tbl
+}
@@ -35,6 +35,11 @@ func GetRow(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, key types
 		return nil, false, err
 	}
 
+	return getRowFromMap(ctx, rowMap, sch, key)
+}
+
+// getRowFromMap returns the row of |rowMap| corresponding to |key| if it exists.
+func getRowFromMap(ctx context.Context, rowMap types.Map, sch schema.Schema, key types.Tuple) (r row.Row, ok bool, err error) {
 	var fields types.Value
 	fields, ok, err = rowMap.MaybeGet(ctx, key)
 	if err != nil || !ok {
@@ -116,6 +121,42 @@ func ReadAllRows(ctx context.Context, rd TableReader, contOnBadRow bool) ([]row.
 	return nil, badRowCount, err
 }
 
+// DrainSqlTableReader reads all rows from |rdr| until io.EOF. Once the reader is exhausted, or a read fails, it is
+// closed if it implements TableCloser or io.Closer. Errors other than io.EOF are returned along with any rows read
+// before the error occurred.
+func DrainSqlTableReader(ctx context.Context, rdr SqlTableReader) (rows []row.Row, err error) {
+	defer func() {
+		closeErr := closeReader(ctx, rdr)
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	for {
+		var r row.Row
+		r, err = rdr.ReadRow(ctx)
+
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return rows, err
+		}
+
+		rows = append(rows, r)
+	}
+}
+
+func closeReader(ctx context.Context, rdr TableReader) error {
+	switch c := rdr.(type) {
+	case TableCloser:
+		return c.Close(ctx)
+	case io.Closer:
+		return c.Close()
+	default:
+		return nil
+	}
+}
+
 // ForeignKeyIsSatisfied ensures that the foreign key is valid by comparing the index data from the given table
 // against the index data from the referenced table.
 func ForeignKeyIsSatisfied(ctx context.Context, fk doltdb.ForeignKey, childIdx, parentIdx types.Map, childDef, parentDef schema.Index) error {
@@ -0,0 +1,66 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+)
+
+type closeTrackingReader struct {
+	*InMemTableReader
+	readErr error
+	closed  bool
+}
+
+func (rd *closeTrackingReader) ReadRow(ctx context.Context) (row.Row, error) {
+	r, err := rd.InMemTableReader.ReadRow(ctx)
+	if err == io.EOF && rd.readErr != nil {
+		return nil, rd.readErr
+	}
+	return r, err
+}
+
+func (rd *closeTrackingReader) Close(ctx context.Context) error {
+	rd.closed = true
+	return nil
+}
+
+func TestDrainSqlTableReader(t *testing.T) {
+	imt := NewInMemTableWithData(rowSch, rows)
+
+	rd := &closeTrackingReader{InMemTableReader: NewInMemTableReader(imt)}
+	results, err := DrainSqlTableReader(context.Background(), rd)
+	require.NoError(t, err)
+	assert.True(t, rd.closed)
+	require.Equal(t, len(rows), len(results))
+	for i := range rows {
+		assert.True(t, row.AreEqual(rows[i], results[i], rowSch))
+	}
+
+	readErr := errors.New("read failure")
+	rd = &closeTrackingReader{InMemTableReader: NewInMemTableReader(imt), readErr: readErr}
+	results, err = DrainSqlTableReader(context.Background(), rd)
+	assert.Equal(t, readErr, err)
+	assert.True(t, rd.closed)
+	assert.Equal(t, len(rows), len(results))
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// jsonlReader is an io.Reader which serializes the rows of a SqlTableReader as newline-delimited JSON.
+type jsonlReader struct {
+	ctx  context.Context
+	rdr  SqlTableReader
+	cols *schema.ColCollection
+	buf  bytes.Buffer
+	err  error
+}
+
+// NewJSONLReader returns an io.Reader of the rows of |rdr| as newline-delimited JSON. Each row is written as a JSON
+// object, one per line, mapping column names to values in schema order, with JSON null for NULL columns. Rows are
+// read from |rdr| only as the returned reader is read, so it can be passed directly to io.Copy. Each copy of a
+// keyless row is written as its own object. Numbers, strings and bools are written as JSON primitives, and values of
+// other types as strings formatted by their column's TypeInfo. |rdr| is read until io.EOF, but is not closed.
+func NewJSONLReader(ctx context.Context, rdr SqlTableReader) io.Reader {
+	return &jsonlReader{ctx: ctx, rdr: rdr, cols: rdr.GetSchema().GetAllCols()}
+}
+
+// Read implements the io.Reader interface.
+func (jr *jsonlReader) Read(p []byte) (int, error) {
+	for jr.buf.Len() == 0 && jr.err == nil {
+		r, err := jr.rdr.ReadRow(jr.ctx)
+		if err != nil {
+			jr.err = err
+			break
+		}
+
+		if err = jr.writeRow(r); err != nil {
+			// don't return a partially written object
+			jr.buf.Reset()
+			jr.err = err
+		}
+	}
+
+	if jr.buf.Len() > 0 {
+		return jr.buf.Read(p)
+	}
+
+	return 0, jr.err
+}
+
+// writeRow appends the JSON object for |r| and a newline to the buffer.
+func (jr *jsonlReader) writeRow(r row.Row) error {
+	jr.buf.WriteByte('{')
+
+	i := 0
+	err := jr.cols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if i > 0 {
+			jr.buf.WriteByte(',')
+		}
+		i++
+
+		name, err := json.Marshal(col.Name)
+		if err != nil {
+			return true, err
+		}
+		jr.buf.Write(name)
+		jr.buf.WriteByte(':')
+
+		val, _ := r.GetColVal(tag)
+		jsonVal, err := jsonlValue(col, val)
+		if err != nil {
+			return true, err
+		}
+
+		data, err := json.Marshal(jsonVal)
+		if err != nil {
+			return true, err
+		}
+		jr.buf.Write(data)
+
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	jr.buf.WriteString("}\n")
+
+	return nil
+}
+
+// jsonlValue returns the value to marshal to JSON for |val|, a value of |col|.
+func jsonlValue(col schema.Column, val types.Value) (interface{}, error) {
+	switch v := val.(type) {
+	case nil, types.Null:
+		return nil, nil
+	case types.Int:
+		return int64(v), nil
+	case types.Uint:
+		return uint64(v), nil
+	case types.Float:
+		return float64(v), nil
+	case types.Bool:
+		return bool(v), nil
+	case types.String:
+		return string(v), nil
+	}
+
+	str, err := col.TypeInfo.FormatValue(val)
+	if err != nil || str == nil {
+		return nil, err
+	}
+
+	return *str, nil
+}
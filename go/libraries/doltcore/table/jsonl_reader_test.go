@@ -0,0 +1,86 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestJSONLReader(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("values and nulls", func(t *testing.T) {
+		coll, err := schema.NewColCollection(
+			schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}),
+			schema.NewColumn("name", 1, types.StringKind, false),
+			schema.NewColumn("rating", 2, types.FloatKind, false),
+		)
+		require.NoError(t, err)
+		sch := schema.MustSchemaFromCols(coll)
+
+		r1, err := row.New(types.Format_Default, sch, row.TaggedValues{0: types.Int(1), 1: types.String(`say "hi"`), 2: types.Float(1.5)})
+		require.NoError(t, err)
+		r2, err := row.New(types.Format_Default, sch, row.TaggedValues{0: types.Int(2)})
+		require.NoError(t, err)
+
+		rdr := NewInMemTableReader(NewInMemTableWithData(sch, []row.Row{r1, r2}))
+		data, err := ioutil.ReadAll(iotest.OneByteReader(NewJSONLReader(ctx, rdr)))
+		require.NoError(t, err)
+
+		expected := `{"id":1,"name":"say \"hi\"","rating":1.5}` + "\n" +
+			`{"id":2,"name":null,"rating":null}` + "\n"
+		assert.Equal(t, expected, string(data))
+	})
+
+	t.Run("keyless rows", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		tbl := newKeylessTestTable(t, mustKeylessSchema(t),
+			keylessTestRow{c0: 0, c1: 5, card: 1},
+			keylessTestRow{c0: 1, c1: 6, card: 3},
+		)
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		defer rdr.Close(ctx)
+
+		data, err := ioutil.ReadAll(NewJSONLReader(ctx, rdr))
+		require.NoError(t, err)
+
+		counts := make(map[int64]uint64)
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			var obj map[string]int64
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &obj))
+			assert.Equal(t, obj["c0"]+5, obj["c1"])
+			counts[obj["c0"]]++
+		}
+		require.NoError(t, scanner.Err())
+		assert.Equal(t, map[int64]uint64{0: 1, 1: 3}, counts)
+	})
+}
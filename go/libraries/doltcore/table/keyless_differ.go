@@ -0,0 +1,118 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// KeylessDiffer walks the row data of two versions of a keyless table in key order, which is the order of the
+// hashes of the rows' contents. Identical rows share a key, so rows from both sides are paired up and their
+// cardinalities compared.
+type KeylessDiffer struct {
+	nbf         *types.NomsBinFormat
+	left, right types.MapIterator
+
+	// the next unconsumed entry from each side, nil once the side is exhausted
+	lKey, lVal types.Value
+	rKey, rVal types.Value
+}
+
+// NewKeylessDiffer creates a KeylessDiffer comparing the row data maps |left| and |right|.
+func NewKeylessDiffer(ctx context.Context, left, right types.Map) (*KeylessDiffer, error) {
+	lIter, err := left.BufferedIterator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rIter, err := right.BufferedIterator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kd := &KeylessDiffer{nbf: left.Format(), left: lIter, right: rIter}
+
+	kd.lKey, kd.lVal, err = lIter.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kd.rKey, kd.rVal, err = rIter.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return kd, nil
+}
+
+// Next returns the next row whose cardinality differs between the two sides, along with its cardinality on the left
+// and on the right. A row that only exists on one side has a cardinality of 0 on the other. Rows with the same
+// cardinality on both sides are skipped. io.EOF is returned once both sides have been read.
+func (kd *KeylessDiffer) Next(ctx context.Context) (r row.Row, leftCard, rightCard uint64, err error) {
+	for {
+		if kd.lKey == nil && kd.rKey == nil {
+			return nil, 0, 0, io.EOF
+		}
+
+		var takeLeft, takeRight bool
+		if kd.lKey == nil {
+			takeRight = true
+		} else if kd.rKey == nil {
+			takeLeft = true
+		} else if kd.lKey.Equals(kd.rKey) {
+			takeLeft, takeRight = true, true
+		} else {
+			takeLeft, err = kd.lKey.Less(kd.nbf, kd.rKey)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			takeRight = !takeLeft
+		}
+
+		if takeLeft {
+			r, leftCard, err = row.KeylessRowsFromTuples(kd.lKey.(types.Tuple), kd.lVal.(types.Tuple))
+			if err != nil {
+				return nil, 0, 0, err
+			}
+
+			kd.lKey, kd.lVal, err = kd.left.Next(ctx)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+		}
+
+		if takeRight {
+			r, rightCard, err = row.KeylessRowsFromTuples(kd.rKey.(types.Tuple), kd.rVal.(types.Tuple))
+			if err != nil {
+				return nil, 0, 0, err
+			}
+
+			kd.rKey, kd.rVal, err = kd.right.Next(ctx)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+		}
+
+		if leftCard != rightCard {
+			return r, leftCard, rightCard, nil
+		}
+
+		leftCard, rightCard = 0, 0
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestKeylessDiffer(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+
+	rowData := func(rows ...keylessTestRow) types.Map {
+		m, err := newKeylessTestTable(t, sch, rows...).GetRowData(ctx)
+		require.NoError(t, err)
+		return m
+	}
+
+	diff := func(left, right types.Map) map[int64][2]uint64 {
+		kd, err := NewKeylessDiffer(ctx, left, right)
+		require.NoError(t, err)
+
+		diffs := make(map[int64][2]uint64)
+		var prevKey types.Value
+		for {
+			r, leftCard, rightCard, err := kd.Next(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			// rows are returned in key order
+			key, err := r.NomsMapKey(sch).Value(ctx)
+			require.NoError(t, err)
+			if prevKey != nil {
+				less, err := prevKey.Less(types.Format_Default, key)
+				require.NoError(t, err)
+				assert.True(t, less)
+			}
+			prevKey = key
+
+			c0, ok := r.GetColVal(keylessC0Tag)
+			require.True(t, ok)
+			diffs[int64(c0.(types.Int))] = [2]uint64{leftCard, rightCard}
+		}
+		return diffs
+	}
+
+	left := rowData(
+		keylessTestRow{c0: 0, c1: 0, card: 3},
+		keylessTestRow{c0: 1, c1: 1, card: 1},
+		keylessTestRow{c0: 2, c1: 2, card: 2},
+		keylessTestRow{c0: 3, c1: 3, card: 1},
+	)
+	right := rowData(
+		keylessTestRow{c0: 0, c1: 0, card: 5},
+		keylessTestRow{c0: 2, c1: 2, card: 2},
+		keylessTestRow{c0: 4, c1: 4, card: 4},
+	)
+	empty := rowData()
+
+	t.Run("added removed and changed", func(t *testing.T) {
+		expected := map[int64][2]uint64{
+			0: {3, 5},
+			1: {1, 0},
+			3: {1, 0},
+			4: {0, 4},
+		}
+		assert.Equal(t, expected, diff(left, right))
+	})
+
+	t.Run("reversed", func(t *testing.T) {
+		expected := map[int64][2]uint64{
+			0: {5, 3},
+			1: {0, 1},
+			3: {0, 1},
+			4: {4, 0},
+		}
+		assert.Equal(t, expected, diff(right, left))
+	})
+
+	t.Run("all added", func(t *testing.T) {
+		expected := map[int64][2]uint64{
+			0: {0, 3},
+			1: {0, 1},
+			2: {0, 2},
+			3: {0, 1},
+		}
+		assert.Equal(t, expected, diff(empty, left))
+	})
+
+	t.Run("all removed", func(t *testing.T) {
+		expected := map[int64][2]uint64{
+			0: {5, 0},
+			2: {2, 0},
+			4: {4, 0},
+		}
+		assert.Equal(t, expected, diff(right, empty))
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		assert.Empty(t, diff(left, left))
+		assert.Empty(t, diff(empty, empty))
+	})
+}
@@ -16,26 +16,429 @@ package table
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
-	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
+// ErrReaderClosed is returned when reading from a table reader that has been closed.
+var ErrReaderClosed = errors.New("table reader is closed")
+
+// cancelCheckInterval is the number of copies of a keyless row that are returned between checks for cancellation
+// of the read context.
+const cancelCheckInterval = 1024
+
+// keylessTableReader reads the rows of a keyless table. Each physical row in the row data map stores the number of
+// identical copies of that row, and the reader returns each copy as a separate logical row.
+type keylessTableReader struct {
+	iter types.MapIterator
+	sch  schema.Schema
+
+	// row is the most recently read physical row, and duplicates is the number of copies of it left to return.
+	row        row.Row
+	duplicates uint64
+
+	// bounded readers stop after returning |remaining| more logical rows, not counting the copies of |row| that are
+	// left in |duplicates|.
+	bounded   bool
+	remaining uint64
+
+	// proj is set for readers that only decode a subset of the table's columns.
+	proj *keylessProjection
+
+	// conv is created by the first call to ReadSqlRow and reused for every row after that.
+	conv *row.SqlRowConverter
+
+	// readers created with WithReusedSqlRows convert each physical row into |sqlRow| once, and return it for every
+	// copy of the row. |converted| is reset whenever a new physical row is read. |row| is still decoded afresh for
+	// each physical row.
+	reuseSqlRows bool
+	sqlRow       sql.Row
+	converted    bool
+
+	closed bool
+}
+
+var _ SqlTableReader = &keylessTableReader{}
+var _ TableCloser = &keylessTableReader{}
+
+// GetSchema implements the TableReader interface.
+func (rdr *keylessTableReader) GetSchema() schema.Schema {
+	return rdr.sch
+}
+
+// ReadRow implements the TableReader interface.
+func (rdr *keylessTableReader) ReadRow(ctx context.Context) (row.Row, error) {
+	if rdr.closed {
+		return nil, ErrReaderClosed
+	}
+
+	if rdr.duplicates == 0 {
+		if err := rdr.nextPhysicalRow(ctx); err != nil {
+			return nil, err
+		}
+	} else if rdr.duplicates%cancelCheckInterval == 0 {
+		// returning copies of a row doesn't touch the map iterator, which is what otherwise notices cancellation
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	rdr.duplicates -= 1
+
+	return rdr.row, nil
+}
+
+// ReadRowWithCardinality reads a physical row along with the number of copies of it, and advances past that row.
+// If some copies of the current row were already returned by ReadRow, only the remaining copies are counted.
+func (rdr *keylessTableReader) ReadRowWithCardinality(ctx context.Context) (row.Row, uint64, error) {
+	if rdr.closed {
+		return nil, 0, ErrReaderClosed
+	}
+
+	if rdr.duplicates == 0 {
+		if err := rdr.nextPhysicalRow(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	card := rdr.duplicates
+	rdr.duplicates = 0
+
+	return rdr.row, card, nil
+}
+
+// nextPhysicalRow reads the next physical row with a non-zero cardinality into |rdr.row| and |rdr.duplicates|.
+func (rdr *keylessTableReader) nextPhysicalRow(ctx context.Context) error {
+	for rdr.duplicates == 0 {
+		if rdr.bounded && rdr.remaining == 0 {
+			return io.EOF
+		}
+
+		key, val, err := rdr.iter.Next(ctx)
+		if err != nil {
+			return err
+		} else if key == nil {
+			return io.EOF
+		}
+
+		if err = rdr.decode(key.(types.Tuple), val.(types.Tuple), 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decode decodes the physical row stored as |key| and |val| into |rdr.row|, and sets |rdr.duplicates| to the number
+// of copies of it after the first |skip|. Bounded readers never return more than |rdr.remaining| copies.
+func (rdr *keylessTableReader) decode(key, val types.Tuple, skip uint64) error {
+	var card uint64
+	var err error
+	if rdr.proj != nil {
+		rdr.row, card, err = rdr.proj.decode(val)
+	} else {
+		rdr.row, card, err = row.KeylessRowsFromTuples(key, val)
+	}
+	if err != nil {
+		return err
+	}
+
+	rdr.duplicates = card - skip
+	if rdr.bounded {
+		if rdr.duplicates > rdr.remaining {
+			rdr.duplicates = rdr.remaining
+		}
+		rdr.remaining -= rdr.duplicates
+	}
+
+	rdr.converted = false
+
+	return nil
+}
+
+// skip advances the reader past its first |n| logical rows. Physical rows with all of their copies skipped only have
+// their cardinality read. If |n| ends partway through the copies of a row, the row is decoded and its remaining copies
+// are the next rows read. Returns io.EOF if the reader has fewer than |n| rows.
+func (rdr *keylessTableReader) skip(ctx context.Context, n uint64) error {
+	for n > 0 {
+		key, val, err := rdr.iter.Next(ctx)
+		if err != nil {
+			return err
+		} else if key == nil {
+			return io.EOF
+		}
+
+		card, err := row.KeylessCardinality(val.(types.Tuple))
+		if err != nil {
+			return err
+		}
+
+		if card <= n {
+			n -= card
+			continue
+		}
+
+		return rdr.decode(key.(types.Tuple), val.(types.Tuple), n)
+	}
+
+	return nil
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rdr *keylessTableReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rdr.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if rdr.conv == nil {
+		rdr.conv = row.NewSqlRowConverter(rdr.sch)
+	}
+
+	if !rdr.reuseSqlRows {
+		return rdr.conv.Convert(r)
+	}
+
+	if !rdr.converted {
+		rdr.sqlRow, err = rdr.conv.ConvertInto(r, rdr.sqlRow)
+		if err != nil {
+			return nil, err
+		}
+		rdr.converted = true
+	}
+
+	return rdr.sqlRow, nil
+}
+
+// Close releases the reader's map iterator. Reads from a closed reader return ErrReaderClosed.
+func (rdr *keylessTableReader) Close(ctx context.Context) error {
+	rdr.iter = nil
+	rdr.row = nil
+	rdr.duplicates = 0
+	rdr.sqlRow = nil
+	rdr.closed = true
+
+	return nil
+}
+
+// newKeylessTableReader creates a reader over all the rows of |tbl|. Callers should Close the reader when they are
+// done with it.
 func newKeylessTableReader(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, buffered bool) (SqlTableReader, error) {
-	return nil, fmt.Errorf("newKeylessTableReader is unimplemented")
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var iter types.MapIterator
+	if buffered {
+		iter, err = rows.BufferedIterator(ctx)
+	} else {
+		iter, err = rows.Iterator(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newKeylessTableReaderForIter(iter, sch), nil
 }
 
-func newKeylessTableReaderForPartition(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, start, end uint64) (SqlTableReader, error) {
-	return nil, fmt.Errorf("newKeylessTableReaderForPartition is unimplemented")
+// newKeylessTableReaderForIter creates a reader over the keyless rows returned by |iter|, which must return the
+// tuple keys and values of the row data map of a keyless table with the schema |sch|.
+func newKeylessTableReaderForIter(iter types.MapIterator, sch schema.Schema) *keylessTableReader {
+	return &keylessTableReader{
+		iter: iter,
+		sch:  sch,
+	}
+}
+
+// newKeylessTableReaderWithProjection creates a reader that returns rows containing only the columns of |sch| with
+// the given |tags|, in the order of |tags|. The schema of the reader is the projected schema. Columns that are not
+// projected are skipped without being decoded. Callers should Close the reader when they are done with it.
+func newKeylessTableReaderWithProjection(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, tags []uint64) (SqlTableReader, error) {
+	proj, err := newKeylessProjection(sch, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := rows.BufferedIterator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keylessTableReader{
+		iter: iter,
+		sch:  proj.sch,
+		proj: proj,
+	}, nil
+}
+
+// keylessProjection decodes the columns of |sch|, a subset of a keyless table's columns, from keyless row values.
+type keylessProjection struct {
+	sch  schema.Schema
+	cols *schema.ColCollection
+}
+
+func newKeylessProjection(sch schema.Schema, tags []uint64) (*keylessProjection, error) {
+	allCols := sch.GetAllCols()
+
+	cols := make([]schema.Column, len(tags))
+	for i, tag := range tags {
+		col, ok := allCols.GetByTag(tag)
+		if !ok {
+			return nil, fmt.Errorf("cannot project unknown column tag %d", tag)
+		}
+		cols[i] = col
+	}
+
+	projCols, err := schema.NewColCollection(cols...)
+	if err != nil {
+		return nil, err
+	}
+
+	projSch, err := schema.SchemaFromCols(projCols)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keylessProjection{sch: projSch, cols: projCols}, nil
+}
+
+// decode reads the cardinality and the projected columns of a keyless row value. Fields are stored in tag order,
+// so decoding stops once every projected column has been found.
+func (proj *keylessProjection) decode(val types.Tuple) (row.Row, uint64, error) {
+	iter, err := val.Iterator()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// skip the cardinality tag
+	if err = iter.Skip(); err != nil {
+		return nil, 0, err
+	}
+
+	_, c, err := iter.Next()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	card, ok := c.(types.Uint)
+	if !ok {
+		return nil, 0, row.ErrInvalidKeylessRow
+	}
+
+	tv := make(row.TaggedValues, proj.cols.Size())
+	for iter.HasMore() && len(tv) < proj.cols.Size() {
+		_, t, err := iter.Next()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		tag, ok := t.(types.Uint)
+		if !ok {
+			return nil, 0, fmt.Errorf("expected tag of type types.Uint, got %v", t)
+		}
+
+		if _, ok = proj.cols.GetByTag(uint64(tag)); !ok {
+			if err = iter.Skip(); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+
+		_, v, err := iter.Next()
+		if err != nil {
+			return nil, 0, err
+		}
+		tv[uint64(tag)] = v
+	}
+
+	r, err := row.New(val.Format(), proj.sch, tv)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return r, uint64(card), nil
+}
+
+// newKeylessTableReaderReverse creates a reader that returns the rows of |tbl| in descending key order. All copies
+// of a physical row are still returned consecutively. Callers should Close the reader when they are done with it.
+func newKeylessTableReaderReverse(ctx context.Context, tbl *doltdb.Table, sch schema.Schema) (SqlTableReader, error) {
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := newReverseMapIterator(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return newKeylessTableReaderForIter(iter, sch), nil
 }
 
-func newKeylessTableReaderForRanges(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, ranges ...*noms.ReadRange) (SqlTableReader, error) {
-	return nil, fmt.Errorf("newKeylessTableReaderForRanges is unimplemented")
+// newKeylessTableReaderForPartition creates a reader over the logical rows of |tbl| with indexes in the half-open
+// interval [start, end), where every copy of a physical row has its own index. A physical row whose copies span a
+// partition boundary has its copies split between the adjacent partitions, so the partitions of a table read each
+// logical row exactly once. Logical indexes can't be looked up in the row data map, so the reader starts by reading
+// the cardinality of each physical row before |start|. Callers should Close the reader when they are done with it.
+func newKeylessTableReaderForPartition(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, start, end uint64) (SqlTableReader, error) {
+	if start > end {
+		return nil, fmt.Errorf("invalid partition table reader, start (%d) > end (%d)", start, end)
+	}
+
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := rows.BufferedIterator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rdr := &keylessTableReader{
+		iter:      iter,
+		sch:       sch,
+		bounded:   true,
+		remaining: end - start,
+	}
+
+	err = rdr.skip(ctx, start)
+	if err == io.EOF {
+		// the partition starts past the end of the table
+		rdr.remaining = 0
+	} else if err != nil {
+		return nil, err
+	}
+
+	return rdr, nil
 }
 
+// newKeylessTableReaderFrom creates a reader over the rows of |tbl| whose map keys are >= |val|. Callers should Close
+// the reader when they are done with it.
 func newKeylessTableReaderFrom(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, val types.Value) (SqlTableReader, error) {
-	return nil, fmt.Errorf("newKeylessTableReaderFrom is unimplemented")
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := rows.IteratorFrom(ctx, val)
+	if err != nil {
+		return nil, err
+	}
+
+	return newKeylessTableReaderForIter(iter, sch), nil
 }
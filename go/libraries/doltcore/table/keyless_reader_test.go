@@ -0,0 +1,842 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	keylessC0Tag = 0
+	keylessC1Tag = 1
+)
+
+type keylessTestRow struct {
+	c0, c1 int64
+	card   uint64
+}
+
+// mustKeylessSchema must be called with schema.FeatureFlagKeylessSchema enabled.
+func mustKeylessSchema(t testing.TB) schema.Schema {
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("c0", keylessC0Tag, types.IntKind, false),
+		schema.NewColumn("c1", keylessC1Tag, types.IntKind, false),
+	)
+	require.NoError(t, err)
+	sch, err := schema.SchemaFromCols(coll)
+	require.NoError(t, err)
+
+	return sch
+}
+
+func newKeylessTestTable(t testing.TB, sch schema.Schema, rows ...keylessTestRow) *doltdb.Table {
+	vrw, err := dbfactory.MemFactory{}.CreateDB(context.Background(), types.Format_Default, nil, nil)
+	require.NoError(t, err)
+
+	return newKeylessTestTableWithVRW(t, vrw, sch, rows...)
+}
+
+// newKeylessTestTableWithVRW creates a keyless table with the given rows whose values are written to |vrw|.
+func newKeylessTestTableWithVRW(t testing.TB, vrw types.ValueReadWriter, sch schema.Schema, rows ...keylessTestRow) *doltdb.Table {
+	ctx := context.Background()
+
+	var kvs []types.Value
+	for _, r := range rows {
+		kr, err := row.NewKeylessRow(vrw.Format(), sch, row.TaggedValues{
+			keylessC0Tag: types.Int(r.c0),
+			keylessC1Tag: types.Int(r.c1),
+		}, r.card)
+		require.NoError(t, err)
+
+		k, err := kr.NomsMapKey(sch).Value(ctx)
+		require.NoError(t, err)
+		v, err := kr.NomsMapValue(sch).Value(ctx)
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	rowData, err := types.NewMap(ctx, vrw, kvs...)
+	require.NoError(t, err)
+	emptyMap, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+	require.NoError(t, err)
+
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData, emptyMap)
+	require.NoError(t, err)
+
+	return tbl
+}
+
+// readKeylessMultiset drains |rdr| and counts the number of times each c0 value was read.
+func readKeylessMultiset(t *testing.T, rdr SqlTableReader) map[int64]uint64 {
+	ctx := context.Background()
+	counts := make(map[int64]uint64)
+	for {
+		r, err := rdr.ReadRow(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		c0, ok := r.GetColVal(keylessC0Tag)
+		require.True(t, ok)
+		counts[int64(c0.(types.Int))]++
+	}
+
+	return counts
+}
+
+func TestKeylessTableReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 0, card: 1},
+		keylessTestRow{c0: 1, c1: 1, card: 2},
+		keylessTestRow{c0: 2, c1: 2, card: 3},
+	)
+
+	rdr, err := NewTableReader(ctx, tbl)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]uint64{0: 1, 1: 2, 2: 3}, readKeylessMultiset(t, rdr))
+
+	rdr, err = NewBufferedTableReader(ctx, tbl)
+	require.NoError(t, err)
+	for i := 0; i < 6; i++ {
+		r, err := rdr.ReadSqlRow(ctx)
+		require.NoError(t, err)
+		require.Len(t, r, 2)
+		assert.Equal(t, r[0], r[1])
+	}
+	_, err = rdr.ReadSqlRow(ctx)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestKeylessTableReaderForPartition(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	const numPartitions = 4
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+
+	var rows []keylessTestRow
+	var numRows uint64
+	expected := make(map[int64]uint64)
+	for i := int64(0); i < 10; i++ {
+		card := uint64(1)
+		if i == 5 {
+			card = 1000
+		}
+		rows = append(rows, keylessTestRow{c0: i, c1: i, card: card})
+		expected[i] = card
+		numRows += card
+	}
+	tbl := newKeylessTestTable(t, sch, rows...)
+
+	actual := make(map[int64]uint64)
+	rowsPerPartition := numRows / numPartitions
+	for i := uint64(0); i < numPartitions; i++ {
+		start := i * rowsPerPartition
+		end := start + rowsPerPartition
+		if i == numPartitions-1 {
+			end = numRows
+		}
+
+		rdr, err := NewBufferedTableReaderForPartition(ctx, tbl, start, end)
+		require.NoError(t, err)
+
+		var partitionRows uint64
+		for c0, count := range readKeylessMultiset(t, rdr) {
+			actual[c0] += count
+			partitionRows += count
+		}
+		assert.Equal(t, end-start, partitionRows, "partition [%d, %d)", start, end)
+	}
+
+	assert.Equal(t, expected, actual)
+
+	t.Run("adjacent partitions split a row", func(t *testing.T) {
+		tbl := newKeylessTestTable(t, sch, keylessTestRow{c0: 7, c1: 7, card: 10})
+
+		partitions := []struct {
+			start, end uint64
+			expected   uint64
+		}{
+			{0, 4, 4},
+			{4, 7, 3},
+			{7, 10, 3},
+			{10, 10, 0},
+			{10, 12, 0},
+			{12, 15, 0},
+		}
+
+		for _, p := range partitions {
+			rdr, err := NewBufferedTableReaderForPartition(ctx, tbl, p.start, p.end)
+			require.NoError(t, err)
+			assert.Equal(t, p.expected, readKeylessMultiset(t, rdr)[7], "partition [%d, %d)", p.start, p.end)
+		}
+
+		rdr, err := NewBufferedTableReaderForPartition(ctx, tbl, 4, 7)
+		require.NoError(t, err)
+		_, card, err := rdr.(cardinalityReader).ReadRowWithCardinality(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), card)
+		_, _, err = rdr.(cardinalityReader).ReadRowWithCardinality(ctx)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("partitions read in order match a full scan", func(t *testing.T) {
+		tbl := newKeylessTestTable(t, sch,
+			keylessTestRow{c0: 1, c1: 1, card: 3},
+			keylessTestRow{c0: 2, c1: 2, card: 5},
+			keylessTestRow{c0: 3, c1: 3, card: 2},
+		)
+
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		expected := readAllSqlRows(t, rdr)
+		require.Len(t, expected, 10)
+
+		var actual []sql.Row
+		for start := uint64(0); start < 10; start += 3 {
+			rdr, err := NewBufferedTableReaderForPartition(ctx, tbl, start, start+3)
+			require.NoError(t, err)
+			actual = append(actual, readAllSqlRows(t, rdr)...)
+		}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("ranges", func(t *testing.T) {
+		_, err := NewTableReaderForRanges(ctx, tbl)
+		assert.Equal(t, ErrNoPrimaryKey, err)
+	})
+}
+
+func TestKeylessTableReaderReverse(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+
+	readC0s := func(rdr SqlTableReader) []int64 {
+		var vals []int64
+		for {
+			r, err := rdr.ReadRow(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			c0, ok := r.GetColVal(keylessC0Tag)
+			require.True(t, ok)
+			vals = append(vals, int64(c0.(types.Int)))
+		}
+		return vals
+	}
+
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 0, card: 1},
+		keylessTestRow{c0: 1, c1: 1, card: 2},
+		keylessTestRow{c0: 2, c1: 2, card: 3},
+		keylessTestRow{c0: 3, c1: 3, card: 1},
+	)
+
+	rdr, err := NewTableReader(ctx, tbl)
+	require.NoError(t, err)
+	forward := readC0s(rdr)
+	require.Len(t, forward, 7)
+
+	rdr, err = NewReverseTableReader(ctx, tbl)
+	require.NoError(t, err)
+	reverse := readC0s(rdr)
+
+	expected := make([]int64, len(forward))
+	for i, v := range forward {
+		expected[len(forward)-1-i] = v
+	}
+	assert.Equal(t, expected, reverse)
+
+	_, err = rdr.ReadRow(ctx)
+	assert.Equal(t, io.EOF, err)
+
+	empty := newKeylessTestTable(t, sch)
+	rdr, err = NewReverseTableReader(ctx, empty)
+	require.NoError(t, err)
+	_, err = rdr.ReadRow(ctx)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestKeylessTableReaderWithCardinality(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 0, card: 1},
+		keylessTestRow{c0: 1, c1: 1, card: 2},
+		keylessTestRow{c0: 2, c1: 2, card: 1000},
+	)
+
+	rdr, err := newKeylessTableReader(ctx, tbl, sch, true)
+	require.NoError(t, err)
+	krdr := rdr.(*keylessTableReader)
+
+	counts := make(map[int64]uint64)
+	for {
+		r, card, err := krdr.ReadRowWithCardinality(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		c0, ok := r.GetColVal(keylessC0Tag)
+		require.True(t, ok)
+		counts[int64(c0.(types.Int))] = card
+	}
+	assert.Equal(t, map[int64]uint64{0: 1, 1: 2, 2: 1000}, counts)
+
+	// mixing ReadRow and ReadRowWithCardinality only counts the copies not yet read
+	rdr, err = newKeylessTableReader(ctx, tbl, sch, true)
+	require.NoError(t, err)
+	krdr = rdr.(*keylessTableReader)
+
+	var total uint64
+	for {
+		_, err := krdr.ReadRow(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		total++
+
+		_, card, err := krdr.ReadRowWithCardinality(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		total += card
+	}
+	assert.Equal(t, uint64(1003), total)
+}
+
+func TestKeylessTableReaderWithProjection(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 10, card: 1},
+		keylessTestRow{c0: 1, c1: 11, card: 2},
+		keylessTestRow{c0: 2, c1: 12, card: 3},
+	)
+
+	t.Run("projected ordering", func(t *testing.T) {
+		rdr, err := NewTableReaderWithProjection(ctx, tbl, []uint64{keylessC1Tag, keylessC0Tag})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c1", "c0"}, rdr.GetSchema().GetAllCols().GetColumnNames())
+
+		counts := make(map[int64]uint64)
+		for {
+			r, err := rdr.ReadSqlRow(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			require.Len(t, r, 2)
+			assert.Equal(t, r[0].(int64), r[1].(int64)+10)
+			counts[r[1].(int64)]++
+		}
+		assert.Equal(t, map[int64]uint64{0: 1, 1: 2, 2: 3}, counts)
+	})
+
+	t.Run("single column", func(t *testing.T) {
+		rdr, err := NewTableReaderWithProjection(ctx, tbl, []uint64{keylessC0Tag})
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]uint64{0: 1, 1: 2, 2: 3}, readKeylessMultiset(t, rdr))
+
+		rdr, err = NewTableReaderWithProjection(ctx, tbl, []uint64{keylessC0Tag})
+		require.NoError(t, err)
+		r, err := rdr.ReadRow(ctx)
+		require.NoError(t, err)
+		_, ok := r.GetColVal(keylessC1Tag)
+		assert.False(t, ok)
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		_, err := NewTableReaderWithProjection(ctx, tbl, []uint64{keylessC0Tag, 42})
+		assert.Error(t, err)
+	})
+}
+
+func TestKeylessTableReaderClose(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 0, card: 3},
+		keylessTestRow{c0: 1, c1: 1, card: 1},
+	)
+
+	rdr, err := NewTableReader(ctx, tbl)
+	require.NoError(t, err)
+	_, err = rdr.ReadRow(ctx)
+	require.NoError(t, err)
+
+	closer, ok := rdr.(TableCloser)
+	require.True(t, ok)
+	require.NoError(t, closer.Close(ctx))
+
+	_, err = rdr.ReadRow(ctx)
+	assert.Equal(t, ErrReaderClosed, err)
+	_, err = rdr.ReadSqlRow(ctx)
+	assert.Equal(t, ErrReaderClosed, err)
+	_, _, err = rdr.(*keylessTableReader).ReadRowWithCardinality(ctx)
+	assert.Equal(t, ErrReaderClosed, err)
+
+	// closing again is a no-op
+	assert.NoError(t, closer.Close(ctx))
+
+	// wrapping readers close the keyless reader
+	rdr, err = NewTableReader(ctx, tbl, WithProgress(1, func(uint64) {}))
+	require.NoError(t, err)
+	require.NoError(t, closeReader(ctx, rdr))
+	_, err = rdr.ReadRow(ctx)
+	assert.Equal(t, ErrReaderClosed, err)
+}
+
+const (
+	wideTableNumCols = 40
+	wideTableNumRows = 10000
+)
+
+func newKeylessWideTable(tb testing.TB) (schema.Schema, *doltdb.Table) {
+	ctx := context.Background()
+
+	var cols []schema.Column
+	for tag := uint64(0); tag < wideTableNumCols; tag++ {
+		cols = append(cols, schema.NewColumn(fmt.Sprintf("c%d", tag), tag, types.IntKind, false))
+	}
+	coll, err := schema.NewColCollection(cols...)
+	require.NoError(tb, err)
+	sch, err := schema.SchemaFromCols(coll)
+	require.NoError(tb, err)
+
+	vrw, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_Default, nil, nil)
+	require.NoError(tb, err)
+
+	emptyMap, err := types.NewMap(ctx, vrw)
+	require.NoError(tb, err)
+	me := emptyMap.Edit()
+	for i := 0; i < wideTableNumRows; i++ {
+		tv := make(row.TaggedValues, wideTableNumCols)
+		for tag := uint64(0); tag < wideTableNumCols; tag++ {
+			tv[tag] = types.Int(i*wideTableNumCols + int(tag))
+		}
+		r, err := row.NewKeylessRow(vrw.Format(), sch, tv, 1)
+		require.NoError(tb, err)
+		me.Set(r.NomsMapKey(sch), r.NomsMapValue(sch))
+	}
+	rowData, err := me.Map(ctx)
+	require.NoError(tb, err)
+
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+	require.NoError(tb, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData, emptyMap)
+	require.NoError(tb, err)
+
+	return sch, tbl
+}
+
+func BenchmarkKeylessTableReaderWithProjection(b *testing.B) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	_, tbl := newKeylessWideTable(b)
+
+	readAll := func(b *testing.B, newReader func() (SqlTableReader, error)) {
+		for i := 0; i < b.N; i++ {
+			rdr, err := newReader()
+			require.NoError(b, err)
+			for {
+				_, err := rdr.ReadSqlRow(ctx)
+				if err == io.EOF {
+					break
+				}
+				require.NoError(b, err)
+			}
+		}
+	}
+
+	b.Run("all columns", func(b *testing.B) {
+		readAll(b, func() (SqlTableReader, error) {
+			return NewBufferedTableReader(ctx, tbl)
+		})
+	})
+
+	b.Run("2 of 40 columns", func(b *testing.B) {
+		readAll(b, func() (SqlTableReader, error) {
+			return NewTableReaderWithProjection(ctx, tbl, []uint64{3, 17})
+		})
+	})
+}
+
+func TestKeylessTableReaderWithReusedSqlRows(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 0, card: 1},
+		keylessTestRow{c0: 1, c1: 10, card: 3},
+		keylessTestRow{c0: 2, c1: 20, card: 2},
+	)
+
+	expected, err := NewTableReader(ctx, tbl)
+	require.NoError(t, err)
+	rdr, err := NewTableReader(ctx, tbl, WithReusedSqlRows())
+	require.NoError(t, err)
+
+	var prev sql.Row
+	for {
+		exp, err := expected.ReadSqlRow(ctx)
+		if err == io.EOF {
+			_, err = rdr.ReadSqlRow(ctx)
+			assert.Equal(t, io.EOF, err)
+			break
+		}
+		require.NoError(t, err)
+
+		r, err := rdr.ReadSqlRow(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, exp, r)
+
+		// every row is converted into the same buffer
+		if prev != nil {
+			assert.Same(t, &prev[0], &r[0])
+		}
+		prev = r
+	}
+}
+
+func TestKeylessTableReaderWithReusedSqlRowsAllocs(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch, keylessTestRow{c0: 1, c1: 10, card: 1000})
+
+	// reads the first copy of the row, then measures reading further copies of it
+	allocsPerCopy := func(t *testing.T, opts ...ReaderOption) float64 {
+		rdr, err := NewTableReader(ctx, tbl, opts...)
+		require.NoError(t, err)
+		_, err = rdr.ReadSqlRow(ctx)
+		require.NoError(t, err)
+
+		return testing.AllocsPerRun(100, func() {
+			_, err := rdr.ReadSqlRow(ctx)
+			require.NoError(t, err)
+		})
+	}
+
+	assert.Equal(t, float64(0), allocsPerCopy(t, WithReusedSqlRows()))
+	assert.True(t, allocsPerCopy(t) >= 1)
+}
+
+// BenchmarkKeylessTableReaderWithReusedSqlRows scans a keyless table of 1M rows with and without WithReusedSqlRows. Run
+// with -benchmem to compare allocations.
+func BenchmarkKeylessTableReaderWithReusedSqlRows(b *testing.B) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	const numRows = 1000000
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(b)
+	rows := make([]keylessTestRow, numRows)
+	for i := range rows {
+		rows[i] = keylessTestRow{c0: int64(i), c1: int64(i), card: 1}
+	}
+	tbl := newKeylessTestTable(b, sch, rows...)
+
+	scan := func(b *testing.B, opts ...ReaderOption) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			rdr, err := NewBufferedTableReader(ctx, tbl, opts...)
+			require.NoError(b, err)
+			for {
+				_, err := rdr.ReadSqlRow(ctx)
+				if err == io.EOF {
+					break
+				}
+				require.NoError(b, err)
+			}
+		}
+	}
+
+	b.Run("new rows", func(b *testing.B) {
+		scan(b)
+	})
+
+	b.Run("reused rows", func(b *testing.B) {
+		scan(b, WithReusedSqlRows())
+	})
+}
+
+func TestKeylessTableReaderCancel(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch, keylessTestRow{c0: 0, c1: 0, card: 10000000})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rdr, err := NewTableReader(ctx, tbl)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, err = rdr.ReadRow(ctx)
+		require.NoError(t, err)
+	}
+
+	cancel()
+
+	for i := 0; i <= cancelCheckInterval; i++ {
+		_, err = rdr.ReadRow(ctx)
+		if err != nil {
+			break
+		}
+	}
+	assert.Equal(t, context.Canceled, err)
+}
+
+// sliceMapIterator is a types.MapIterator over a fixed slice of keys and values.
+type sliceMapIterator struct {
+	kvs []types.Value
+}
+
+func (itr *sliceMapIterator) Next(ctx context.Context) (k, v types.Value, err error) {
+	if len(itr.kvs) == 0 {
+		return nil, nil, nil
+	}
+
+	k, v = itr.kvs[0], itr.kvs[1]
+	itr.kvs = itr.kvs[2:]
+
+	return k, v, nil
+}
+
+// newKeylessTestIter creates an iterator over the map entries of |rows|, in the order given. Unlike a row data map,
+// the entries may have a cardinality of 0.
+func newKeylessTestIter(t *testing.T, sch schema.Schema, rows ...keylessTestRow) *sliceMapIterator {
+	ctx := context.Background()
+
+	var kvs []types.Value
+	for _, r := range rows {
+		kr, err := row.NewKeylessRow(types.Format_Default, sch, row.TaggedValues{
+			keylessC0Tag: types.Int(r.c0),
+			keylessC1Tag: types.Int(r.c1),
+		}, r.card)
+		require.NoError(t, err)
+
+		k, err := kr.NomsMapKey(sch).Value(ctx)
+		require.NoError(t, err)
+		v, err := kr.NomsMapValue(sch).Value(ctx)
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	return &sliceMapIterator{kvs: kvs}
+}
+
+func TestKeylessTableReaderForIter(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+
+	tests := []struct {
+		name     string
+		rows     []keylessTestRow
+		expected map[int64]uint64
+	}{
+		{
+			name:     "no rows",
+			expected: map[int64]uint64{},
+		},
+		{
+			name: "cardinality 1",
+			rows: []keylessTestRow{
+				{c0: 0, c1: 0, card: 1},
+				{c0: 1, c1: 1, card: 1},
+			},
+			expected: map[int64]uint64{0: 1, 1: 1},
+		},
+		{
+			name: "cardinality 0 is skipped",
+			rows: []keylessTestRow{
+				{c0: 0, c1: 0, card: 0},
+				{c0: 1, c1: 1, card: 2},
+				{c0: 2, c1: 2, card: 0},
+				{c0: 3, c1: 3, card: 0},
+				{c0: 4, c1: 4, card: 1},
+				{c0: 5, c1: 5, card: 0},
+			},
+			expected: map[int64]uint64{1: 2, 4: 1},
+		},
+		{
+			name: "only cardinality 0",
+			rows: []keylessTestRow{
+				{c0: 0, c1: 0, card: 0},
+			},
+			expected: map[int64]uint64{},
+		},
+		{
+			name: "large cardinality",
+			rows: []keylessTestRow{
+				{c0: 0, c1: 0, card: 1},
+				{c0: 1, c1: 1, card: 100000},
+				{c0: 2, c1: 2, card: 1},
+			},
+			expected: map[int64]uint64{0: 1, 1: 100000, 2: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rdr := newKeylessTableReaderForIter(newKeylessTestIter(t, sch, test.rows...), sch)
+			assert.Equal(t, test.expected, readKeylessMultiset(t, rdr))
+
+			// reads after the end of the rows keep returning io.EOF
+			_, err := rdr.ReadRow(ctx)
+			assert.Equal(t, io.EOF, err)
+			_, _, err = rdr.ReadRowWithCardinality(ctx)
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func TestKeylessTableReaderZeroCardinality(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+
+	// a row data map should never contain a row with a cardinality of 0, but readers skip them if it does
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 0, card: 0},
+		keylessTestRow{c0: 1, c1: 1, card: 2},
+		keylessTestRow{c0: 2, c1: 2, card: 0},
+		keylessTestRow{c0: 3, c1: 3, card: 1},
+	)
+	expected := map[int64]uint64{1: 2, 3: 1}
+
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		newReader func() (SqlTableReader, error)
+		expected  map[int64]uint64
+	}{
+		{
+			name: "forward",
+			newReader: func() (SqlTableReader, error) {
+				return NewTableReader(ctx, tbl)
+			},
+			expected: expected,
+		},
+		{
+			name: "reverse",
+			newReader: func() (SqlTableReader, error) {
+				return NewReverseTableReader(ctx, tbl)
+			},
+			expected: expected,
+		},
+		{
+			name: "projection",
+			newReader: func() (SqlTableReader, error) {
+				return NewTableReaderWithProjection(ctx, tbl, []uint64{keylessC0Tag})
+			},
+			expected: expected,
+		},
+		{
+			name: "partition",
+			newReader: func() (SqlTableReader, error) {
+				return newKeylessTableReaderForPartition(ctx, tbl, sch, 0, rowData.Len())
+			},
+			expected: expected,
+		},
+		{
+			name: "distinct",
+			newReader: func() (SqlTableReader, error) {
+				return NewDistinctKeylessReader(ctx, tbl)
+			},
+			expected: map[int64]uint64{1: 1, 3: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rdr, err := test.newReader()
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, readKeylessMultiset(t, rdr))
+		})
+	}
+
+	t.Run("with cardinality", func(t *testing.T) {
+		rdr, err := NewDistinctKeylessReader(ctx, tbl)
+		require.NoError(t, err)
+
+		var cards []uint64
+		for {
+			_, card, err := rdr.ReadRowWithCardinality(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			cards = append(cards, card)
+		}
+		assert.ElementsMatch(t, []uint64{2, 1}, cards)
+	})
+}
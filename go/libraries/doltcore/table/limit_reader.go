@@ -0,0 +1,115 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+)
+
+// LimitReader is a SqlTableReader which returns at most a fixed number of rows from another reader, and then returns
+// io.EOF. Each copy of a keyless row counts as a row. Once the limit is reached, the LimitReader reads one more row
+// from the underlying reader to find out whether any rows were left unread, which is reported by Truncated.
+type LimitReader struct {
+	SqlTableReader
+	remaining uint64
+	truncated bool
+	done      bool
+}
+
+var _ SqlTableReader = &LimitReader{}
+
+// NewLimitReader creates a LimitReader which returns the first |limit| rows of |rdr|.
+func NewLimitReader(rdr SqlTableReader, limit uint64) *LimitReader {
+	return &LimitReader{SqlTableReader: rdr, remaining: limit}
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *LimitReader) ReadRow(ctx context.Context) (row.Row, error) {
+	if err := rd.checkLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	r, err := rd.SqlTableReader.ReadRow(ctx)
+	if err != nil {
+		return nil, rd.checkEOF(err)
+	}
+
+	rd.remaining--
+
+	return r, nil
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *LimitReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	if err := rd.checkLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	r, err := rd.SqlTableReader.ReadSqlRow(ctx)
+	if err != nil {
+		return nil, rd.checkEOF(err)
+	}
+
+	rd.remaining--
+
+	return r, nil
+}
+
+// Truncated returns whether the underlying reader had rows left when the limit was reached. It is always false until
+// the LimitReader has returned io.EOF.
+func (rd *LimitReader) Truncated() bool {
+	return rd.truncated
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *LimitReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
+
+// checkLimit returns io.EOF once the limit has been reached, checking whether the underlying reader has more rows
+// the first time it does.
+func (rd *LimitReader) checkLimit(ctx context.Context) error {
+	if rd.done {
+		return io.EOF
+	}
+
+	if rd.remaining > 0 {
+		return nil
+	}
+
+	_, err := rd.SqlTableReader.ReadRow(ctx)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	rd.truncated = err == nil
+	rd.done = true
+
+	return io.EOF
+}
+
+// checkEOF records that the underlying reader ran out of rows before the limit was reached.
+func (rd *LimitReader) checkEOF(err error) error {
+	if err == io.EOF {
+		rd.done = true
+	}
+
+	return err
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+func TestLimitReader(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		numRows   int
+		limit     uint64
+		expected  int
+		truncated bool
+	}{
+		{name: "more rows than limit", numRows: 10, limit: 4, expected: 4, truncated: true},
+		{name: "rows equal to limit", numRows: 4, limit: 4, expected: 4, truncated: false},
+		{name: "fewer rows than limit", numRows: 3, limit: 4, expected: 3, truncated: false},
+		{name: "zero limit", numRows: 3, limit: 0, expected: 0, truncated: true},
+		{name: "empty table", numRows: 0, limit: 0, expected: 0, truncated: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rdr, err := NewTableReader(ctx, newKeyedTestTable(t, test.numRows))
+			require.NoError(t, err)
+			lr := NewLimitReader(rdr, test.limit)
+			defer lr.Close(ctx)
+
+			rows := readAllSqlRows(t, lr)
+			assert.Len(t, rows, test.expected)
+			assert.Equal(t, test.truncated, lr.Truncated())
+
+			// further reads keep returning io.EOF
+			_, err = lr.ReadRow(ctx)
+			assert.Equal(t, io.EOF, err)
+			assert.Equal(t, test.truncated, lr.Truncated())
+		})
+	}
+
+	t.Run("keyless copies count as rows", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		sch := mustKeylessSchema(t)
+		tbl := newKeylessTestTable(t, sch,
+			keylessTestRow{c0: 0, c1: 0, card: 3},
+			keylessTestRow{c0: 1, c1: 1, card: 2},
+		)
+
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		lr := NewLimitReader(rdr, 4)
+
+		// the copies of the first row read are all returned, along with one copy of the other row
+		var total uint64
+		counts := readKeylessMultiset(t, lr)
+		for _, n := range counts {
+			total += n
+		}
+		assert.Len(t, counts, 2)
+		assert.Equal(t, uint64(4), total)
+		assert.True(t, lr.Truncated())
+
+		rdr, err = NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		lr = NewLimitReader(rdr, 5)
+		assert.Equal(t, map[int64]uint64{0: 3, 1: 2}, readKeylessMultiset(t, lr))
+		assert.False(t, lr.Truncated())
+	})
+}
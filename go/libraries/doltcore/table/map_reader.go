@@ -0,0 +1,107 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// RowMapFunc transforms a row read by a MapReader. The returned row must match the schema given to the MapReader.
+type RowMapFunc func(r row.Row) (row.Row, error)
+
+// MapReader is a SqlTableReader which transforms each row read from another reader with a RowMapFunc. Since the
+// transformation can change the shape of rows, the schema of the transformed rows is supplied when creating the
+// reader. The copies of a keyless row are transformed together when read with ReadRowWithCardinality, and the
+// cardinality is passed through unchanged.
+type MapReader struct {
+	SqlTableReader
+	sch  schema.Schema
+	fn   RowMapFunc
+	conv *row.SqlRowConverter
+}
+
+var _ SqlTableReader = &MapReader{}
+var _ cardinalityReader = &MapReader{}
+
+// NewMapReader creates a MapReader which returns the rows of |rdr| transformed by |fn|, with the schema |sch|.
+func NewMapReader(rdr SqlTableReader, sch schema.Schema, fn RowMapFunc) *MapReader {
+	return &MapReader{
+		SqlTableReader: rdr,
+		sch:            sch,
+		fn:             fn,
+		conv:           row.NewSqlRowConverter(sch),
+	}
+}
+
+// GetSchema returns the schema of the transformed rows.
+func (rd *MapReader) GetSchema() schema.Schema {
+	return rd.sch
+}
+
+// ReadRow implements the TableReader interface. An error returned by the transformation is returned as a read
+// error.
+func (rd *MapReader) ReadRow(ctx context.Context) (row.Row, error) {
+	r, err := rd.SqlTableReader.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.fn(r)
+}
+
+// ReadRowWithCardinality reads and transforms a row along with the number of copies of it. If the underlying reader
+// cannot return cardinalities, each row is returned with a cardinality of 1.
+func (rd *MapReader) ReadRowWithCardinality(ctx context.Context) (row.Row, uint64, error) {
+	cardRdr, ok := rd.SqlTableReader.(cardinalityReader)
+	if !ok {
+		r, err := rd.ReadRow(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		return r, 1, nil
+	}
+
+	r, card, err := cardRdr.ReadRowWithCardinality(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r, err = rd.fn(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return r, card, nil
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *MapReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.conv.Convert(r)
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *MapReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
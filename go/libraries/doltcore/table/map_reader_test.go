@@ -0,0 +1,113 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestMapReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 5, card: 1},
+		keylessTestRow{c0: 1, c1: 6, card: 2},
+		keylessTestRow{c0: 2, c1: 7, card: 3},
+	)
+
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("doubled", keylessC0Tag, types.IntKind, false),
+		schema.NewColumn("c1", keylessC1Tag, types.IntKind, false),
+	)
+	require.NoError(t, err)
+	outSch := schema.UnkeyedSchemaFromCols(coll)
+
+	double := func(r row.Row) (row.Row, error) {
+		c0, _ := r.GetColVal(keylessC0Tag)
+		c1, _ := r.GetColVal(keylessC1Tag)
+		return row.New(r.Format(), outSch, row.TaggedValues{
+			keylessC0Tag: types.Int(int64(c0.(types.Int)) * 2),
+			keylessC1Tag: c1,
+		})
+	}
+
+	t.Run("read rows", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		mrd := NewMapReader(rdr, outSch, double)
+		defer mrd.Close(ctx)
+
+		assert.Equal(t, outSch, mrd.GetSchema())
+		assert.Equal(t, map[int64]uint64{0: 1, 2: 2, 4: 3}, readKeylessMultiset(t, mrd))
+	})
+
+	t.Run("read sql rows", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl, WithOrderBy(keylessC0Tag))
+		require.NoError(t, err)
+		mrd := NewMapReader(rdr, outSch, double)
+		defer mrd.Close(ctx)
+
+		rows := readAllSqlRows(t, mrd)
+		require.Len(t, rows, 6)
+		assert.Equal(t, []interface{}{int64(0), int64(5)}, []interface{}(rows[0]))
+		assert.Equal(t, []interface{}{int64(4), int64(7)}, []interface{}(rows[5]))
+	})
+
+	t.Run("cardinality passes through", func(t *testing.T) {
+		rdr, err := NewDistinctKeylessReader(ctx, tbl)
+		require.NoError(t, err)
+		mrd := NewMapReader(rdr, outSch, double)
+		defer mrd.Close(ctx)
+
+		counts := make(map[int64]uint64)
+		for {
+			r, card, err := mrd.ReadRowWithCardinality(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			doubled, ok := r.GetColVal(keylessC0Tag)
+			require.True(t, ok)
+			counts[int64(doubled.(types.Int))] = card
+		}
+		assert.Equal(t, map[int64]uint64{0: 1, 2: 2, 4: 3}, counts)
+	})
+
+	t.Run("transform error", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		fnErr := errors.New("transform failure")
+		mrd := NewMapReader(rdr, outSch, func(r row.Row) (row.Row, error) { return nil, fnErr })
+		defer mrd.Close(ctx)
+
+		_, err = mrd.ReadRow(ctx)
+		assert.Equal(t, fnErr, err)
+		_, err = mrd.ReadSqlRow(ctx)
+		assert.Equal(t, fnErr, err)
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+var ErrNoReaders = errors.New("at least one reader is required")
+var ErrReaderSchemasDiffer = errors.New("readers must have the same schema")
+
+// MultiTableReader is a SqlTableReader which concatenates the rows of several SqlTableReaders. Rows are read from
+// each reader in turn, moving on to the next reader when the current one returns io.EOF.
+type MultiTableReader struct {
+	sch     schema.Schema
+	readers []SqlTableReader
+	idx     int
+}
+
+var _ SqlTableReader = &MultiTableReader{}
+
+// NewMultiTableReader creates a MultiTableReader from |readers|. The schemas of the readers are validated here, and
+// ErrReaderSchemasDiffer is returned if they do not all agree.
+func NewMultiTableReader(readers []SqlTableReader) (*MultiTableReader, error) {
+	if len(readers) == 0 {
+		return nil, ErrNoReaders
+	}
+
+	sch := readers[0].GetSchema()
+	for _, rdr := range readers[1:] {
+		eq, err := schema.SchemasAreEqual(sch, rdr.GetSchema())
+		if err != nil {
+			return nil, err
+		} else if !eq {
+			return nil, ErrReaderSchemasDiffer
+		}
+	}
+
+	return &MultiTableReader{sch: sch, readers: readers}, nil
+}
+
+// GetSchema implements the TableReader interface.
+func (rd *MultiTableReader) GetSchema() schema.Schema {
+	return rd.sch
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *MultiTableReader) ReadRow(ctx context.Context) (row.Row, error) {
+	for rd.idx < len(rd.readers) {
+		r, err := rd.readers[rd.idx].ReadRow(ctx)
+		if err == io.EOF {
+			rd.idx++
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		return r, nil
+	}
+
+	return nil, io.EOF
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *MultiTableReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	for rd.idx < len(rd.readers) {
+		r, err := rd.readers[rd.idx].ReadSqlRow(ctx)
+		if err == io.EOF {
+			rd.idx++
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		return r, nil
+	}
+
+	return nil, io.EOF
+}
+
+// Close closes every sub-reader that can be closed, and returns the first error encountered.
+func (rd *MultiTableReader) Close(ctx context.Context) error {
+	var firstErr error
+	for _, rdr := range rd.readers {
+		err := closeReader(ctx, rdr)
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
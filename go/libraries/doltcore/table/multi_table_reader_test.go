@@ -0,0 +1,115 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestMultiTableReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+
+	tables := [][]keylessTestRow{
+		{{c0: 0, c1: 0, card: 1}, {c0: 1, c1: 1, card: 2}},
+		{},
+		{{c0: 2, c1: 2, card: 3}},
+		{},
+		{},
+		{{c0: 3, c1: 3, card: 1}, {c0: 4, c1: 4, card: 1}, {c0: 5, c1: 5, card: 4}},
+	}
+
+	newReaders := func() []SqlTableReader {
+		var readers []SqlTableReader
+		for _, rows := range tables {
+			rdr, err := NewTableReader(ctx, newKeylessTestTable(t, sch, rows...))
+			require.NoError(t, err)
+			readers = append(readers, rdr)
+		}
+		return readers
+	}
+
+	t.Run("read rows", func(t *testing.T) {
+		rdr, err := NewMultiTableReader(newReaders())
+		require.NoError(t, err)
+		eq, err := schema.SchemasAreEqual(sch, rdr.GetSchema())
+		require.NoError(t, err)
+		assert.True(t, eq)
+
+		expected := map[int64]uint64{0: 1, 1: 2, 2: 3, 3: 1, 4: 1, 5: 4}
+		assert.Equal(t, expected, readKeylessMultiset(t, rdr))
+		assert.NoError(t, rdr.Close(ctx))
+	})
+
+	t.Run("read sql rows", func(t *testing.T) {
+		rdr, err := NewMultiTableReader(newReaders())
+		require.NoError(t, err)
+
+		var count int
+		for {
+			r, err := rdr.ReadSqlRow(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			require.Len(t, r, 2)
+			count++
+		}
+		assert.Equal(t, 12, count)
+
+		_, err = rdr.ReadSqlRow(ctx)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("only empty readers", func(t *testing.T) {
+		empty, err := NewTableReader(ctx, newKeylessTestTable(t, sch))
+		require.NoError(t, err)
+		rdr, err := NewMultiTableReader([]SqlTableReader{empty})
+		require.NoError(t, err)
+
+		_, err = rdr.ReadRow(ctx)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("no readers", func(t *testing.T) {
+		_, err := NewMultiTableReader(nil)
+		assert.Equal(t, ErrNoReaders, err)
+	})
+
+	t.Run("schemas differ", func(t *testing.T) {
+		coll, err := schema.NewColCollection(
+			schema.NewColumn("c0", keylessC0Tag, types.IntKind, true, schema.NotNullConstraint{}),
+			schema.NewColumn("c1", keylessC1Tag, types.IntKind, false),
+		)
+		require.NoError(t, err)
+		pkSch, err := schema.SchemaFromCols(coll)
+		require.NoError(t, err)
+
+		readers := append(newReaders(), NewInMemTableReader(NewInMemTable(pkSch)))
+		_, err = NewMultiTableReader(readers)
+		assert.Equal(t, ErrReaderSchemasDiffer, err)
+	})
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// partitionsPerWorker is the number of partitions ParallelScan creates for each worker. Creating more partitions
+// than workers lets workers that finish early take more partitions from the shared queue while slower ones are busy.
+// A partition is never split once a worker has taken it.
+const partitionsPerWorker = 4
+
+var ErrInvalidWorkerCount = errors.New("worker count must be greater than 0")
+
+// ParallelScan calls |fn| for each row of |tbl|, using |workers| goroutines. The table's row data is split into
+// partitions which the workers take from a shared queue until none are left. Every copy of a keyless row is passed
+// to |fn|, so it is called once per logical row. |fn| is called concurrently and in no particular order. The first
+// error returned by |fn| or by a read cancels the remaining workers and is returned.
+func ParallelScan(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, workers int, fn func(row.Row) error) error {
+	if workers <= 0 {
+		return ErrInvalidWorkerCount
+	}
+
+	partitions, err := partitionTable(ctx, tbl, uint64(workers*partitionsPerWorker))
+	if err != nil {
+		return err
+	}
+
+	queue := make(chan TablePartition, len(partitions))
+	for _, p := range partitions {
+		queue <- p
+	}
+	close(queue)
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	for i := 0; i < workers; i++ {
+		eg.Go(func() error {
+			for p := range queue {
+				if err := scanPartition(egCtx, p, sch, fn); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	return eg.Wait()
+}
+
+// scanPartition calls |fn| for each row of |p|, stopping early if |ctx| is canceled. An error closing the partition's
+// reader is returned if nothing else failed.
+func scanPartition(ctx context.Context, p TablePartition, sch schema.Schema, fn func(row.Row) error) (err error) {
+	var rdr SqlTableReader
+	if schema.IsKeyless(sch) {
+		rdr, err = newKeylessTableReaderForPartition(ctx, p.tbl, sch, p.start, p.end)
+	} else {
+		rdr, err = newPkTableReaderForPartition(ctx, p.tbl, sch, p.start, p.end)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := closeReader(ctx, rdr)
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		r, err := rdr.ReadRow(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+}
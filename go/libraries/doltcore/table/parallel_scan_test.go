@@ -0,0 +1,106 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestParallelScan(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+
+	var keylessRows []keylessTestRow
+	var logicalCount uint64
+	for i := int64(0); i < 25; i++ {
+		card := uint64(i%4) + 1
+		if i == 7 {
+			card = 500
+		}
+		keylessRows = append(keylessRows, keylessTestRow{c0: i, c1: i, card: card})
+		logicalCount += card
+	}
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch, keylessRows...)
+
+	t.Run("every logical row once", func(t *testing.T) {
+		for _, workers := range []int{1, 3, 8, 40} {
+			var mu sync.Mutex
+			actual := make(map[int64]uint64)
+			err := ParallelScan(ctx, tbl, sch, workers, func(r row.Row) error {
+				c0, ok := r.GetColVal(keylessC0Tag)
+				if !ok {
+					return errors.New("missing c0")
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				actual[int64(c0.(types.Int))]++
+				return nil
+			})
+			require.NoError(t, err)
+
+			var total uint64
+			for i, r := range keylessRows {
+				assert.Equal(t, r.card, actual[int64(i)], "%d workers, c0 = %d", workers, i)
+				total += actual[int64(i)]
+			}
+			assert.Equal(t, logicalCount, total, "%d workers", workers)
+		}
+	})
+
+	t.Run("keyed", func(t *testing.T) {
+		keyed := newKeyedTestTable(t, 100)
+		keyedSch, err := keyed.GetSchema(ctx)
+		require.NoError(t, err)
+
+		var count int64
+		err = ParallelScan(ctx, keyed, keyedSch, 4, func(r row.Row) error {
+			atomic.AddInt64(&count, 1)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), count)
+	})
+
+	t.Run("error propagates", func(t *testing.T) {
+		fnErr := errors.New("row failure")
+		var count int64
+		err := ParallelScan(ctx, tbl, sch, 4, func(r row.Row) error {
+			if atomic.AddInt64(&count, 1) == 100 {
+				return fnErr
+			}
+			return nil
+		})
+		assert.Equal(t, fnErr, err)
+	})
+
+	t.Run("invalid worker count", func(t *testing.T) {
+		err := ParallelScan(ctx, tbl, sch, 0, func(r row.Row) error { return nil })
+		assert.Equal(t, ErrInvalidWorkerCount, err)
+	})
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+var ErrInvalidPartitionCount = errors.New("partition count must be greater than 0")
+
+// PartitionedTableReader splits the rows of a table into partitions which can be read independently, and in
+// parallel.
+type PartitionedTableReader interface {
+	// Partitions returns |n| SqlTableReaders, each reading a contiguous range of the table's rows. The ranges do not
+	// overlap, and reading every partition in order reads the same rows, in the same order, as a full scan of the
+	// table. Some partitions will be empty if the table has fewer than |n| rows.
+	Partitions(ctx context.Context, n uint64) ([]SqlTableReader, error)
+}
+
+type tablePartitioner struct {
+	tbl *doltdb.Table
+}
+
+var _ PartitionedTableReader = tablePartitioner{}
+
+// NewPartitionedTableReader creates a PartitionedTableReader for |tbl|. Keyless tables are split by logical row, so
+// the copies of a physical row may be read by more than one partition.
+func NewPartitionedTableReader(tbl *doltdb.Table) PartitionedTableReader {
+	return tablePartitioner{tbl: tbl}
+}
+
+// Partitions implements the PartitionedTableReader interface.
+func (tp tablePartitioner) Partitions(ctx context.Context, n uint64) ([]SqlTableReader, error) {
+	partitions, err := partitionTable(ctx, tp.tbl, n)
+	if err != nil {
+		return nil, err
+	}
+
+	readers := make([]SqlTableReader, n)
+	for i, p := range partitions {
+		readers[i], err = p.Reader(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return readers, nil
+}
+
+// partitionTable splits the rows of |tbl| into |n| contiguous partitions. The sizes of the partitions differ by at
+// most one row, with the larger partitions first, so any empty partitions are at the end.
+func partitionTable(ctx context.Context, tbl *doltdb.Table, n uint64) ([]TablePartition, error) {
+	if n == 0 {
+		return nil, ErrInvalidPartitionCount
+	}
+
+	numElements, err := EstimateRowCount(ctx, tbl)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsPerPartition := numElements / n
+	remainder := numElements % n
+
+	partitions := make([]TablePartition, n)
+	start := uint64(0)
+	for i := uint64(0); i < n; i++ {
+		end := start + itemsPerPartition
+		if i < remainder {
+			end++
+		}
+
+		partitions[i] = TablePartition{tbl: tbl, start: start, end: end}
+		start = end
+	}
+
+	return partitions, nil
+}
+
+// TablePartition is a contiguous range of the rows of a table. It implements sql.Partition, so the SQL engine
+// can hand partitions out to parallel workers, each of which reads its partition with Reader.
+type TablePartition struct {
+	tbl *doltdb.Table
+	// start is the first index of this partition (inclusive)
+	start uint64
+	// all elements in the partition will be less than end (exclusive)
+	end uint64
+}
+
+var _ sql.Partition = TablePartition{}
+
+// Key implements the sql.Partition interface. Keys are unique among the partitions of a table.
+func (p TablePartition) Key() []byte {
+	return []byte(strconv.FormatUint(p.start, 10) + " >= i < " + strconv.FormatUint(p.end, 10))
+}
+
+// Reader returns a buffered SqlTableReader over the rows of the partition.
+func (p TablePartition) Reader(ctx context.Context) (SqlTableReader, error) {
+	return NewBufferedTableReaderForPartition(ctx, p.tbl, p.start, p.end)
+}
+
+// PartitionIter is a sql.PartitionIter which returns each of a table's TablePartitions exactly once. It is safe for
+// concurrent use.
+type PartitionIter struct {
+	i          int
+	mu         *sync.Mutex
+	partitions []TablePartition
+}
+
+var _ sql.PartitionIter = (*PartitionIter)(nil)
+
+// NewPartitionIter creates a PartitionIter over |n| partitions of |tbl|. If the table has fewer than |n| rows, there is
+// one partition per row instead, or a single empty partition if the table has no rows.
+func NewPartitionIter(ctx context.Context, tbl *doltdb.Table, n uint64) (*PartitionIter, error) {
+	partitions, err := partitionTable(ctx, tbl, n)
+	if err != nil {
+		return nil, err
+	}
+
+	// empty partitions would all have the same key
+	for len(partitions) > 1 && partitions[len(partitions)-1].start == partitions[len(partitions)-1].end {
+		partitions = partitions[:len(partitions)-1]
+	}
+
+	return &PartitionIter{mu: &sync.Mutex{}, partitions: partitions}, nil
+}
+
+// Next returns the next partition if there is one, or io.EOF if there isn't.
+func (itr *PartitionIter) Next() (sql.Partition, error) {
+	itr.mu.Lock()
+	defer itr.mu.Unlock()
+
+	if itr.i >= len(itr.partitions) {
+		return nil, io.EOF
+	}
+
+	partition := itr.partitions[itr.i]
+	itr.i++
+
+	return partition, nil
+}
+
+// Close is required by the sql.PartitionIter interface. Does nothing.
+func (itr *PartitionIter) Close() error {
+	return nil
+}
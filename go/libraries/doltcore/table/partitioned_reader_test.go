@@ -0,0 +1,200 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func newKeyedTestTable(t *testing.T, numRows int) *doltdb.Table {
+	vrw, err := dbfactory.MemFactory{}.CreateDB(context.Background(), types.Format_Default, nil, nil)
+	require.NoError(t, err)
+
+	return newKeyedTestTableWithVRW(t, vrw, numRows)
+}
+
+// newKeyedTestTableWithVRW creates a table with columns (pk, val), where val = pk * 10, whose values are written to
+// |vrw|.
+func newKeyedTestTableWithVRW(t *testing.T, vrw types.ValueReadWriter, numRows int) *doltdb.Table {
+	ctx := context.Background()
+
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("val", 1, types.IntKind, false),
+	)
+	require.NoError(t, err)
+	sch, err := schema.SchemaFromCols(coll)
+	require.NoError(t, err)
+
+	emptyMap, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	me := emptyMap.Edit()
+	for i := 0; i < numRows; i++ {
+		r, err := row.New(vrw.Format(), sch, row.TaggedValues{0: types.Int(i), 1: types.Int(i * 10)})
+		require.NoError(t, err)
+		me.Set(r.NomsMapKey(sch), r.NomsMapValue(sch))
+	}
+	rowData, err := me.Map(ctx)
+	require.NoError(t, err)
+
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData, emptyMap)
+	require.NoError(t, err)
+
+	return tbl
+}
+
+func readAllSqlRows(t *testing.T, rdr SqlTableReader) []sql.Row {
+	var rows []sql.Row
+	for {
+		r, err := rdr.ReadSqlRow(context.Background())
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+}
+
+func TestPartitionedTableReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+
+	var keylessRows []keylessTestRow
+	for i := int64(0); i < 25; i++ {
+		keylessRows = append(keylessRows, keylessTestRow{c0: i, c1: i, card: uint64(i%4) + 1})
+	}
+	keylessRows[7].card = 500
+
+	tables := map[string]*doltdb.Table{
+		"keyed":         newKeyedTestTable(t, 25),
+		"keyed empty":   newKeyedTestTable(t, 0),
+		"keyless":       newKeylessTestTable(t, mustKeylessSchema(t), keylessRows...),
+		"keyless empty": newKeylessTestTable(t, mustKeylessSchema(t)),
+	}
+
+	for name, tbl := range tables {
+		t.Run(name, func(t *testing.T) {
+			rdr, err := NewTableReader(ctx, tbl)
+			require.NoError(t, err)
+			expected := readAllSqlRows(t, rdr)
+
+			for _, n := range []uint64{1, 2, 3, 4, 7, 25, 40} {
+				partitions, err := NewPartitionedTableReader(tbl).Partitions(ctx, n)
+				require.NoError(t, err)
+				require.Len(t, partitions, int(n))
+
+				var actual []sql.Row
+				for _, p := range partitions {
+					actual = append(actual, readAllSqlRows(t, p)...)
+				}
+				assert.Equal(t, expected, actual, "%d partitions", n)
+			}
+
+			_, err = NewPartitionedTableReader(tbl).Partitions(ctx, 0)
+			assert.Equal(t, ErrInvalidPartitionCount, err)
+		})
+	}
+}
+
+func TestPartitionIter(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+
+	var keylessRows []keylessTestRow
+	for i := int64(0); i < 10; i++ {
+		keylessRows = append(keylessRows, keylessTestRow{c0: i, c1: i, card: uint64(i) + 1})
+	}
+	tbl := newKeylessTestTable(t, mustKeylessSchema(t), keylessRows...)
+
+	rdr, err := NewTableReader(ctx, tbl)
+	require.NoError(t, err)
+	expected := readAllSqlRows(t, rdr)
+	require.Len(t, expected, 55)
+
+	itr, err := NewPartitionIter(ctx, tbl, 4)
+	require.NoError(t, err)
+
+	keys := make(map[string]bool)
+	var actual []sql.Row
+	for {
+		p, err := itr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		key := string(p.Key())
+		assert.False(t, keys[key], "duplicate partition key %s", key)
+		keys[key] = true
+
+		prdr, err := p.(TablePartition).Reader(ctx)
+		require.NoError(t, err)
+		actual = append(actual, readAllSqlRows(t, prdr)...)
+	}
+	require.NoError(t, itr.Close())
+
+	assert.Len(t, keys, 4)
+	assert.Equal(t, expected, actual)
+
+	_, err = NewPartitionIter(ctx, tbl, 0)
+	assert.Equal(t, ErrInvalidPartitionCount, err)
+
+	partitionSizes := func(t *testing.T, tbl *doltdb.Table, n uint64) []uint64 {
+		itr, err := NewPartitionIter(ctx, tbl, n)
+		require.NoError(t, err)
+
+		var sizes []uint64
+		for {
+			p, err := itr.Next()
+			if err == io.EOF {
+				return sizes
+			}
+			require.NoError(t, err)
+			sizes = append(sizes, p.(TablePartition).end-p.(TablePartition).start)
+		}
+	}
+
+	t.Run("rows spread evenly", func(t *testing.T) {
+		assert.Equal(t, []uint64{3, 3, 2, 2}, partitionSizes(t, newKeyedTestTable(t, 10), 4))
+		assert.Equal(t, []uint64{14, 14, 14, 13}, partitionSizes(t, tbl, 4))
+	})
+
+	t.Run("fewer rows than partitions", func(t *testing.T) {
+		assert.Equal(t, []uint64{1, 1, 1}, partitionSizes(t, newKeyedTestTable(t, 3), 5))
+	})
+
+	t.Run("empty table", func(t *testing.T) {
+		assert.Equal(t, []uint64{0}, partitionSizes(t, newKeyedTestTable(t, 0), 5))
+	})
+}
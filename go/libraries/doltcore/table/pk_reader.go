@@ -75,9 +75,9 @@ func newPkTableReader(ctx context.Context, tbl *doltdb.Table, sch schema.Schema,
 
 	var iter types.MapIterator
 	if buffered {
-		iter, err = rows.Iterator(ctx)
-	} else {
 		iter, err = rows.BufferedIterator(ctx)
+	} else {
+		iter, err = rows.Iterator(ctx)
 	}
 	if err != nil {
 		return nil, err
@@ -115,6 +115,36 @@ func newPkTableReaderFrom(ctx context.Context, tbl *doltdb.Table, sch schema.Sch
 	}, nil
 }
 
+func newPkTableReaderReverse(ctx context.Context, tbl *doltdb.Table, sch schema.Schema) (SqlTableReader, error) {
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := newReverseMapIterator(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkTableReader{
+		iter: iter,
+		sch:  sch,
+	}, nil
+}
+
+// newReverseMapIterator returns a MapIterator over |rows| starting at the last entry and moving towards the first.
+func newReverseMapIterator(ctx context.Context, rows types.Map) (types.MapIterator, error) {
+	last, _, err := rows.Last(ctx)
+	if err != nil {
+		return nil, err
+	} else if last == nil {
+		// empty map, any iterator will return EOF immediately
+		return rows.Iterator(ctx)
+	}
+
+	return rows.IteratorBackFrom(ctx, last)
+}
+
 type partitionTableReader struct {
 	SqlTableReader
 	remaining uint64
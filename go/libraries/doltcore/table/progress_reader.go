@@ -0,0 +1,109 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+)
+
+// ProgressReader is a SqlTableReader which counts the rows read from another reader, and reports the count along
+// with the total number of rows to be read, if it is known. Every copy of a keyless row counts as a row. Readers
+// created with WithProgress are ProgressReaders that also call a callback every |every| rows.
+type ProgressReader struct {
+	SqlTableReader
+	rowsRead   uint64
+	total      uint64
+	totalKnown bool
+
+	// cb is called with |rowsRead| each time another |every| rows have been read, if it is set.
+	cb    func(rowsRead uint64)
+	every uint64
+}
+
+var _ SqlTableReader = &ProgressReader{}
+
+// NewProgressReader creates a ProgressReader over the rows of |rdr|, for which the total number of rows is not known.
+func NewProgressReader(rdr SqlTableReader) *ProgressReader {
+	return &ProgressReader{SqlTableReader: rdr}
+}
+
+// NewTableProgressReader creates a ProgressReader over every row of |tbl|, with reader options |opts|. The total is
+// the length of the row data map for keyed tables, and the sum of the row cardinalities for keyless tables, which
+// are summed before the reader is returned. If |opts| include WithProgress, its callback is called by the returned
+// reader.
+func NewTableProgressReader(ctx context.Context, tbl *doltdb.Table, opts ...ReaderOption) (*ProgressReader, error) {
+	total, err := EstimateRowCount(ctx, tbl)
+	if err != nil {
+		return nil, err
+	}
+
+	rdr, err := NewTableReader(ctx, tbl, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, ok := rdr.(*ProgressReader)
+	if !ok {
+		pr = NewProgressReader(rdr)
+	}
+	pr.total, pr.totalKnown = total, true
+
+	return pr, nil
+}
+
+// ReadRow implements the TableReader interface.
+func (rdr *ProgressReader) ReadRow(ctx context.Context) (row.Row, error) {
+	r, err := rdr.SqlTableReader.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rdr.rowRead()
+	return r, nil
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rdr *ProgressReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rdr.SqlTableReader.ReadSqlRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rdr.rowRead()
+	return r, nil
+}
+
+// Progress returns the number of rows read so far, and the total number of rows. |ok| is false if the total is not
+// known, in which case only |read| is meaningful.
+func (rdr *ProgressReader) Progress() (read uint64, total uint64, ok bool) {
+	return rdr.rowsRead, rdr.total, rdr.totalKnown
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rdr *ProgressReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rdr.SqlTableReader)
+}
+
+func (rdr *ProgressReader) rowRead() {
+	rdr.rowsRead++
+	if rdr.cb != nil && rdr.rowsRead%rdr.every == 0 {
+		rdr.cb(rdr.rowsRead)
+	}
+}
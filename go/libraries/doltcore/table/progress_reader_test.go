@@ -0,0 +1,166 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+func TestProgressTableReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+
+	// 1 + 2 + 7 + 1 = 11 logical rows
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 0, card: 1},
+		keylessTestRow{c0: 1, c1: 1, card: 2},
+		keylessTestRow{c0: 2, c1: 2, card: 7},
+		keylessTestRow{c0: 3, c1: 3, card: 1},
+	)
+
+	tests := []struct {
+		name     string
+		every    uint64
+		expected []uint64
+	}{
+		{"every row", 1, []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+		{"every 3 rows", 3, []uint64{3, 6, 9}},
+		{"every 11 rows", 11, []uint64{11}},
+		{"less often than the row count", 12, nil},
+		{"disabled", 0, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls []uint64
+			rdr, err := NewTableReader(ctx, tbl, WithProgress(test.every, func(rowsRead uint64) {
+				calls = append(calls, rowsRead)
+			}))
+			require.NoError(t, err)
+
+			var count uint64
+			for {
+				_, err := rdr.ReadSqlRow(ctx)
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				count++
+			}
+			assert.Equal(t, uint64(11), count)
+			assert.Equal(t, test.expected, calls)
+
+			// reading past the end doesn't report progress
+			_, err = rdr.ReadRow(ctx)
+			assert.Equal(t, io.EOF, err)
+			assert.Equal(t, test.expected, calls)
+		})
+	}
+
+	t.Run("nil callback", func(t *testing.T) {
+		rdr, err := NewBufferedTableReader(ctx, tbl, WithProgress(1, nil))
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]uint64{0: 1, 1: 2, 2: 7, 3: 1}, readKeylessMultiset(t, rdr))
+	})
+}
+
+func TestProgressReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+
+	tables := map[string]*doltdb.Table{
+		"keyed": newKeyedTestTable(t, 25),
+		"keyless": newKeylessTestTable(t, mustKeylessSchema(t),
+			keylessTestRow{c0: 0, c1: 0, card: 1},
+			keylessTestRow{c0: 1, c1: 1, card: 9},
+			keylessTestRow{c0: 2, c1: 2, card: 3},
+		),
+		"empty": newKeyedTestTable(t, 0),
+	}
+	totals := map[string]uint64{"keyed": 25, "keyless": 13, "empty": 0}
+
+	for name, tbl := range tables {
+		t.Run(name, func(t *testing.T) {
+			rdr, err := NewTableProgressReader(ctx, tbl)
+			require.NoError(t, err)
+			defer rdr.Close(ctx)
+
+			read, total, ok := rdr.Progress()
+			require.True(t, ok)
+			assert.Equal(t, uint64(0), read)
+			assert.Equal(t, totals[name], total)
+
+			var prev uint64
+			for {
+				_, err := rdr.ReadSqlRow(ctx)
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+
+				read, _, _ = rdr.Progress()
+				assert.Equal(t, prev+1, read)
+				prev = read
+			}
+
+			read, total, ok = rdr.Progress()
+			assert.True(t, ok)
+			assert.Equal(t, total, read)
+		})
+	}
+
+	t.Run("unknown total", func(t *testing.T) {
+		inner, err := NewTableReader(ctx, newKeyedTestTable(t, 3))
+		require.NoError(t, err)
+		rdr := NewProgressReader(inner)
+
+		_, err = rdr.ReadRow(ctx)
+		require.NoError(t, err)
+		read, _, ok := rdr.Progress()
+		assert.False(t, ok)
+		assert.Equal(t, uint64(1), read)
+	})
+	t.Run("progress callback", func(t *testing.T) {
+		var calls []uint64
+		rdr, err := NewTableProgressReader(ctx, newKeyedTestTable(t, 10), WithProgress(4, func(rowsRead uint64) {
+			calls = append(calls, rowsRead)
+		}))
+		require.NoError(t, err)
+
+		// the callback is reported by the same reader, not a second wrapper around it
+		_, ok := rdr.SqlTableReader.(*ProgressReader)
+		assert.False(t, ok)
+
+		assert.Len(t, readAllSqlRows(t, rdr), 10)
+		assert.Equal(t, []uint64{4, 8}, calls)
+		read, total, ok := rdr.Progress()
+		assert.True(t, ok)
+		assert.Equal(t, uint64(10), read)
+		assert.Equal(t, uint64(10), total)
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrNoPrimaryKey is returned when creating a reader that only supports keyed tables for a keyless table.
+var ErrNoPrimaryKey = errors.New("table has no primary key")
+
+// RandomAccessReader is a SqlTableReader over a keyed table which also supports point lookups by primary key.
+// Lookups with GetByKey can be interleaved with sequential reads, and don't change the position of the scan. Both
+// read from the row data of the table when the reader was created.
+type RandomAccessReader struct {
+	SqlTableReader
+	rows types.Map
+	sch  schema.Schema
+}
+
+var _ SqlTableReader = &RandomAccessReader{}
+
+// NewRandomAccessReader creates a RandomAccessReader for |tbl|, which must have a primary key.
+func NewRandomAccessReader(ctx context.Context, tbl *doltdb.Table) (*RandomAccessReader, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.IsKeyless(sch) {
+		return nil, ErrNoPrimaryKey
+	}
+
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := rows.BufferedIterator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RandomAccessReader{
+		SqlTableReader: pkTableReader{iter: iter, sch: sch},
+		rows:           rows,
+		sch:            sch,
+	}, nil
+}
+
+// GetByKey returns the row with the primary key |key|, a tuple of the key columns' tags and values as returned by
+// row.Row.NomsMapKey. The returned bool is false if there is no such row.
+func (rd *RandomAccessReader) GetByKey(ctx context.Context, key types.Tuple) (row.Row, bool, error) {
+	return getRowFromMap(ctx, rd.rows, rd.sch, key)
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *RandomAccessReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRandomAccessReader(t *testing.T) {
+	ctx := context.Background()
+	tbl := newKeyedTestTable(t, 10)
+
+	pkKey := func(pk int64) types.Tuple {
+		key, err := types.NewTuple(types.Format_Default, types.Uint(0), types.Int(pk))
+		require.NoError(t, err)
+		return key
+	}
+
+	rd, err := NewRandomAccessReader(ctx, tbl)
+	require.NoError(t, err)
+	defer rd.Close(ctx)
+
+	for i := int64(0); i < 10; i++ {
+		r, err := rd.ReadSqlRow(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, i, r[0])
+
+		// lookups between reads don't move the scan
+		lookup := (i * 7) % 10
+		found, ok, err := rd.GetByKey(ctx, pkKey(lookup))
+		require.NoError(t, err)
+		require.True(t, ok)
+		val, ok := found.GetColVal(1)
+		require.True(t, ok)
+		assert.Equal(t, types.Int(lookup*10), val)
+	}
+
+	_, err = rd.ReadRow(ctx)
+	assert.Equal(t, io.EOF, err)
+
+	found, ok, err := rd.GetByKey(ctx, pkKey(100))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, found)
+
+	t.Run("keyless table", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		_, err := NewRandomAccessReader(ctx, newKeylessTestTable(t, mustKeylessSchema(t)))
+		assert.Equal(t, ErrNoPrimaryKey, err)
+	})
+}
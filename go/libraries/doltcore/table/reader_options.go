@@ -0,0 +1,89 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+// ReaderOption configures optional behavior of the SqlTableReaders created by this package.
+type ReaderOption func(opts *readerOptions)
+
+type readerOptions struct {
+	progress      func(rowsRead uint64)
+	progressEvery uint64
+	orderBy       []uint64
+	reuseSqlRows  bool
+}
+
+// WithProgress returns a ReaderOption that calls |cb| with the number of rows read so far each time another |n| rows
+// have been read. Every copy of a keyless row counts as a row. |cb| is never called once the reader has returned
+// io.EOF. A nil |cb| or an |n| of 0 disables progress reporting. Otherwise the reader returned is a *ProgressReader.
+func WithProgress(n uint64, cb func(rowsRead uint64)) ReaderOption {
+	return func(opts *readerOptions) {
+		opts.progress = cb
+		opts.progressEvery = n
+	}
+}
+
+// WithOrderBy returns a ReaderOption that makes the reader return rows sorted by the columns with the given |tags|,
+// in ascending order with NULLs first, rather than in key order. Rows that compare equal keep their key order, so the
+// output is deterministic. All copies of a keyless row are returned together.
+//
+// Sorting requires buffering every row of the table in memory before the first row is returned. Keyless rows are
+// buffered once per physical row, along with their cardinality, rather than once per copy.
+func WithOrderBy(tags ...uint64) ReaderOption {
+	return func(opts *readerOptions) {
+		opts.orderBy = tags
+	}
+}
+
+// WithReusedSqlRows returns a ReaderOption that makes keyless table readers convert rows read with ReadSqlRow into a
+// single sql.Row owned by the reader, rather than allocating a new sql.Row for every row. All copies of a physical row
+// are returned from the same conversion, so returning another copy of a row allocates nothing. Only the sql.Row is
+// reused: decoding each physical row from the map still allocates, and rows returned by ReadRow are unaffected. A
+// sql.Row returned in this mode is only valid until the next call to ReadSqlRow, and callers that keep rows must copy
+// them. Readers of keyed tables ignore this option.
+func WithReusedSqlRows() ReaderOption {
+	return func(opts *readerOptions) {
+		opts.reuseSqlRows = true
+	}
+}
+
+// applyReaderOptions wraps |rdr| as needed to implement the behavior requested by |opts|.
+func applyReaderOptions(rdr SqlTableReader, opts []ReaderOption) (SqlTableReader, error) {
+	var ro readerOptions
+	for _, opt := range opts {
+		opt(&ro)
+	}
+
+	if kr, ok := rdr.(*keylessTableReader); ok && ro.reuseSqlRows {
+		kr.reuseSqlRows = true
+	}
+
+	if len(ro.orderBy) > 0 {
+		var err error
+		rdr, err = newSortedTableReader(rdr, ro.orderBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ro.progress != nil && ro.progressEvery > 0 {
+		rdr = &ProgressReader{
+			SqlTableReader: rdr,
+			cb:             ro.progress,
+			every:          ro.progressEvery,
+		}
+	}
+
+	return rdr, nil
+}
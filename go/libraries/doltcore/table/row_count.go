@@ -0,0 +1,59 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// EstimateRowCount returns the number of rows in |tbl| without reading the rows through a table reader. For tables
+// with a primary key this is the length of the row data map. For keyless tables the cardinality of each physical row
+// is read from its map value, and neither the map keys nor the columns of the rows are decoded.
+func EstimateRowCount(ctx context.Context, tbl *doltdb.Table) (uint64, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if !schema.IsKeyless(sch) {
+		return rowData.Len(), nil
+	}
+
+	var count uint64
+	err = rowData.IterValues(ctx, func(value types.Value) error {
+		card, err := row.KeylessCardinality(value.(types.Tuple))
+		if err != nil {
+			return err
+		}
+
+		count += card
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
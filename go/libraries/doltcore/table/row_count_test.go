@@ -0,0 +1,99 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+func TestEstimateRowCount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keyed", func(t *testing.T) {
+		tbl := newKeyedTestTable(t, 17)
+
+		count, err := EstimateRowCount(ctx, tbl)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(17), count)
+	})
+
+	t.Run("keyless", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		sch := mustKeylessSchema(t)
+		tbl := newKeylessTestTable(t, sch,
+			keylessTestRow{c0: 0, c1: 0, card: 1},
+			keylessTestRow{c0: 1, c1: 1, card: 2},
+			keylessTestRow{c0: 2, c1: 2, card: 1000},
+		)
+
+		count, err := EstimateRowCount(ctx, tbl)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1003), count)
+	})
+
+	t.Run("empty keyless", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		tbl := newKeylessTestTable(t, mustKeylessSchema(t))
+
+		count, err := EstimateRowCount(ctx, tbl)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), count)
+	})
+}
+
+func BenchmarkEstimateRowCount(b *testing.B) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	_, tbl := newKeylessWideTable(b)
+
+	b.Run("EstimateRowCount", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			count, err := EstimateRowCount(ctx, tbl)
+			require.NoError(b, err)
+			require.Equal(b, uint64(wideTableNumRows), count)
+		}
+	})
+
+	b.Run("table reader", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rdr, err := NewBufferedTableReader(ctx, tbl)
+			require.NoError(b, err)
+
+			var count uint64
+			for {
+				_, err := rdr.ReadRow(ctx)
+				if err == io.EOF {
+					break
+				}
+				require.NoError(b, err)
+				count++
+			}
+			require.Equal(b, uint64(wideTableNumRows), count)
+		}
+	})
+}
@@ -0,0 +1,289 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrMalformedRowEncoding is returned by a RowDecoder when its input is not a stream written by a row encoder.
+var ErrMalformedRowEncoding = errors.New("malformed row encoding")
+
+const (
+	rowFrameLenSize  = 4
+	rowFrameCardSize = 8
+)
+
+// rowEncoder is an io.Reader which serializes the rows of a SqlTableReader in a binary format read by RowDecoder.
+type rowEncoder struct {
+	ctx     context.Context
+	rdr     SqlTableReader
+	cardRdr cardinalityReader
+	buf     bytes.Buffer
+	err     error
+}
+
+// NewRowEncoder returns an io.Reader of the rows of |rdr| encoded as a stream of frames, one for each physical row.
+// Each frame is a 4 byte big-endian length of the rest of the frame, an 8 byte big-endian cardinality, and the row's
+// non-null values as a noms tuple of alternating tags and values, in the same binary format noms uses to store them.
+// The encoding does not depend on the byte order or word size of the machine writing it, and every noms kind a
+// column can hold round-trips through a RowDecoder unchanged. If |rdr| can return rows with their cardinality, each
+// keyless row is written once with its number of copies. Otherwise every row is written with a cardinality of 1.
+// Rows are read from |rdr| only as the returned reader is read, and |rdr| is read until io.EOF but is not closed.
+func NewRowEncoder(ctx context.Context, rdr SqlTableReader) io.Reader {
+	cardRdr, _ := rdr.(cardinalityReader)
+	return &rowEncoder{ctx: ctx, rdr: rdr, cardRdr: cardRdr}
+}
+
+// Read implements the io.Reader interface.
+func (re *rowEncoder) Read(p []byte) (int, error) {
+	for re.buf.Len() == 0 && re.err == nil {
+		r, card, err := re.readRow()
+		if err != nil {
+			re.err = err
+			break
+		}
+
+		if err = re.writeRow(r, card); err != nil {
+			re.err = err
+		}
+	}
+
+	if re.buf.Len() > 0 {
+		return re.buf.Read(p)
+	}
+
+	return 0, re.err
+}
+
+func (re *rowEncoder) readRow() (row.Row, uint64, error) {
+	if re.cardRdr != nil {
+		return re.cardRdr.ReadRowWithCardinality(re.ctx)
+	}
+
+	r, err := re.rdr.ReadRow(re.ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return r, 1, nil
+}
+
+// writeRow appends the frame for |r| to the buffer.
+func (re *rowEncoder) writeRow(r row.Row, card uint64) error {
+	tpl, err := taggedValuesTuple(r)
+	if err != nil {
+		return err
+	}
+
+	c, err := types.EncodeValue(tpl, r.Format())
+	if err != nil {
+		return err
+	}
+	data := c.Data()
+
+	var hdr [rowFrameLenSize + rowFrameCardSize]byte
+	binary.BigEndian.PutUint32(hdr[:rowFrameLenSize], uint32(rowFrameCardSize+len(data)))
+	binary.BigEndian.PutUint64(hdr[rowFrameLenSize:], card)
+	re.buf.Write(hdr[:])
+	re.buf.Write(data)
+
+	return nil
+}
+
+// taggedValuesTuple returns a noms tuple of the tags and values of the non-null columns of |r|, in tag order.
+func taggedValuesTuple(r row.Row) (types.Tuple, error) {
+	tv, err := row.GetTaggedVals(r)
+	if err != nil {
+		return types.EmptyTuple(r.Format()), err
+	}
+
+	// the columns of a keyed row aren't iterated in a fixed order
+	tags := make([]uint64, 0, len(tv))
+	for tag, val := range tv {
+		if !types.IsNull(val) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+	vals := make([]types.Value, 0, 2*len(tags))
+	for _, tag := range tags {
+		vals = append(vals, types.Uint(tag), tv[tag])
+	}
+
+	return types.NewTuple(r.Format(), vals...)
+}
+
+// RowDecoder is a SqlTableReader which reads rows from a stream written by NewRowEncoder. As with a keyless table
+// reader, ReadRow returns each copy of a keyless row separately, and ReadRowWithCardinality returns each physical row
+// once along with its remaining number of copies.
+type RowDecoder struct {
+	rd   io.Reader
+	sch  schema.Schema
+	vrw  types.ValueReadWriter
+	conv *row.SqlRowConverter
+
+	row        row.Row
+	duplicates uint64
+}
+
+var _ SqlTableReader = &RowDecoder{}
+var _ cardinalityReader = &RowDecoder{}
+
+// NewRowDecoder creates a RowDecoder reading rows of the schema |sch| from |rd|. Values are decoded with the format
+// of |vrw|, which must match the format of the rows that were encoded.
+func NewRowDecoder(rd io.Reader, sch schema.Schema, vrw types.ValueReadWriter) *RowDecoder {
+	return &RowDecoder{rd: rd, sch: sch, vrw: vrw, conv: row.NewSqlRowConverter(sch)}
+}
+
+// GetSchema implements the TableReader interface.
+func (rd *RowDecoder) GetSchema() schema.Schema {
+	return rd.sch
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *RowDecoder) ReadRow(ctx context.Context) (row.Row, error) {
+	if rd.duplicates == 0 {
+		if err := rd.nextFrame(); err != nil {
+			return nil, err
+		}
+	}
+
+	rd.duplicates -= 1
+
+	return rd.row, nil
+}
+
+// ReadRowWithCardinality reads a physical row along with the number of copies of it, and advances past that row.
+func (rd *RowDecoder) ReadRowWithCardinality(ctx context.Context) (row.Row, uint64, error) {
+	if rd.duplicates == 0 {
+		if err := rd.nextFrame(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	card := rd.duplicates
+	rd.duplicates = 0
+
+	return rd.row, card, nil
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *RowDecoder) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.conv.Convert(r)
+}
+
+// Close implements the TableReadCloser interface. The underlying io.Reader is not closed.
+func (rd *RowDecoder) Close(ctx context.Context) error {
+	rd.row = nil
+	rd.duplicates = 0
+	return nil
+}
+
+// nextFrame reads the next frame with a non-zero cardinality into |rd.row| and |rd.duplicates|. io.EOF is returned
+// only if the stream ends between frames.
+func (rd *RowDecoder) nextFrame() error {
+	for rd.duplicates == 0 {
+		var lenBuf [rowFrameLenSize]byte
+		if _, err := io.ReadFull(rd.rd, lenBuf[:]); err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("%w: truncated frame length", ErrMalformedRowEncoding)
+		} else if err != nil {
+			return err
+		}
+
+		frameLen := binary.BigEndian.Uint32(lenBuf[:])
+		if frameLen <= rowFrameCardSize {
+			return fmt.Errorf("%w: frame length %d is too short", ErrMalformedRowEncoding, frameLen)
+		}
+
+		frame := make([]byte, frameLen)
+		if _, err := io.ReadFull(rd.rd, frame); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("%w: truncated frame", ErrMalformedRowEncoding)
+		} else if err != nil {
+			return err
+		}
+
+		card := binary.BigEndian.Uint64(frame[:rowFrameCardSize])
+		if !schema.IsKeyless(rd.sch) && card > 1 {
+			return fmt.Errorf("%w: keyed row has a cardinality of %d", ErrMalformedRowEncoding, card)
+		}
+
+		r, err := rd.decodeRow(frame[rowFrameCardSize:], card)
+		if err != nil {
+			return err
+		}
+
+		rd.row, rd.duplicates = r, card
+	}
+
+	return nil
+}
+
+// decodeRow decodes the noms tuple of tags and values in |data| as a row of the decoder's schema.
+func (rd *RowDecoder) decodeRow(data []byte, card uint64) (row.Row, error) {
+	val, err := types.DecodeValue(chunks.NewChunk(data), rd.vrw)
+	if err != nil {
+		return nil, err
+	}
+
+	tpl, ok := val.(types.Tuple)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a tuple, found %s", ErrMalformedRowEncoding, val.Kind().String())
+	}
+
+	vals, err := tpl.AsSlice()
+	if err != nil {
+		return nil, err
+	} else if len(vals)%2 != 0 {
+		return nil, fmt.Errorf("%w: tuple has an odd number of fields", ErrMalformedRowEncoding)
+	}
+
+	cols := rd.sch.GetAllCols()
+	tv := make(row.TaggedValues, len(vals)/2)
+	for i := 0; i < len(vals); i += 2 {
+		tag, ok := vals[i].(types.Uint)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected a uint tag, found %s", ErrMalformedRowEncoding, vals[i].Kind().String())
+		}
+		if _, ok := cols.GetByTag(uint64(tag)); !ok {
+			return nil, fmt.Errorf("%w: tag %d is not in the schema", ErrMalformedRowEncoding, uint64(tag))
+		}
+		tv[uint64(tag)] = vals[i+1]
+	}
+
+	if schema.IsKeyless(rd.sch) {
+		return row.NewKeylessRow(rd.vrw.Format(), rd.sch, tv, card)
+	}
+	return row.New(rd.vrw.Format(), rd.sch, tv)
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+	"testing/iotest"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRowEncoderRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	vrw, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_Default, nil, nil)
+	require.NoError(t, err)
+
+	mustTypeInfo := func(sqlType sql.Type) typeinfo.TypeInfo {
+		ti, err := typeinfo.FromSqlType(sqlType)
+		require.NoError(t, err)
+		return ti
+	}
+
+	// one column for every kind of noms value a column can hold, along with a value to store in it
+	colTypes := []struct {
+		name string
+		ti   typeinfo.TypeInfo
+		val  interface{}
+	}{
+		{"i", typeinfo.Int64Type, int64(-42)},
+		{"u", typeinfo.Uint64Type, uint64(42)},
+		{"f", typeinfo.Float64Type, 3.25},
+		{"b", typeinfo.BoolType, true},
+		{"s", typeinfo.StringDefaultType, "a string with \x00 and unicode ☃"},
+		{"id", typeinfo.UuidType, "5b3bdd5e-dd62-4d4b-b0a8-3c4e6a6e5a79"},
+		{"blob", typeinfo.InlineBlobType, "\x00\x01\x02\xff"},
+		{"dec", mustTypeInfo(sql.MustCreateDecimalType(10, 3)), "-1234.567"},
+		{"dt", typeinfo.DatetimeType, "2020-06-08 13:14:15"},
+		{"tm", typeinfo.TimeType, "-12:34:56"},
+		{"yr", typeinfo.YearType, int16(2020)},
+		{"bits", mustTypeInfo(sql.MustCreateBitType(10)), uint64(1000)},
+		{"e", mustTypeInfo(sql.MustCreateEnumType([]string{"a", "b", "c"}, sql.Collation_Default)), "b"},
+		{"st", mustTypeInfo(sql.MustCreateSetType([]string{"a", "b", "c"}, sql.Collation_Default)), "a,c"},
+	}
+
+	cols := []schema.Column{schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{})}
+	tv := row.TaggedValues{0: types.Int(1)}
+	for i, ct := range colTypes {
+		tag := uint64(i + 1)
+		col, err := schema.NewColumnWithTypeInfo(ct.name, tag, ct.ti, false, "", false, "")
+		require.NoError(t, err)
+		cols = append(cols, col)
+
+		tv[tag], err = ct.ti.ConvertValueToNomsValue(ct.val)
+		require.NoError(t, err)
+	}
+	coll, err := schema.NewColCollection(cols...)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(coll)
+
+	full, err := row.New(vrw.Format(), sch, tv)
+	require.NoError(t, err)
+	nulls, err := row.New(vrw.Format(), sch, row.TaggedValues{0: types.Int(2)})
+	require.NoError(t, err)
+	rows := []row.Row{full, nulls}
+
+	t.Run("every column type", func(t *testing.T) {
+		rdr := NewInMemTableReader(NewInMemTableWithData(sch, rows))
+		data, err := ioutil.ReadAll(iotest.OneByteReader(NewRowEncoder(ctx, rdr)))
+		require.NoError(t, err)
+
+		dec := NewRowDecoder(bytes.NewReader(data), sch, vrw)
+		for _, expected := range rows {
+			r, err := dec.ReadRow(ctx)
+			require.NoError(t, err)
+			assert.True(t, row.AreEqual(expected, r, sch))
+		}
+		_, err = dec.ReadRow(ctx)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("sql rows", func(t *testing.T) {
+		expected := readAllSqlRows(t, NewInMemTableReader(NewInMemTableWithData(sch, rows)))
+
+		rdr := NewInMemTableReader(NewInMemTableWithData(sch, rows))
+		dec := NewRowDecoder(NewRowEncoder(ctx, rdr), sch, vrw)
+		actual := readAllSqlRows(t, dec)
+		assert.Equal(t, expected, actual)
+		for _, v := range actual[1][1:] {
+			assert.Nil(t, v)
+		}
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		dec := NewRowDecoder(bytes.NewReader(nil), sch, vrw)
+		_, err := dec.ReadRow(ctx)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		rdr := NewInMemTableReader(NewInMemTableWithData(sch, rows))
+		data, err := ioutil.ReadAll(NewRowEncoder(ctx, rdr))
+		require.NoError(t, err)
+
+		for _, n := range []int{2, rowFrameLenSize + 1, len(data) - 1} {
+			dec := NewRowDecoder(bytes.NewReader(data[:n]), sch, vrw)
+			var err error
+			for err == nil {
+				_, err = dec.ReadRow(ctx)
+			}
+			assert.True(t, errors.Is(err, ErrMalformedRowEncoding), "truncated to %d bytes: %v", n, err)
+		}
+	})
+}
+
+func TestRowEncoderKeyless(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	vrw, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_Default, nil, nil)
+	require.NoError(t, err)
+
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 1, c1: 10, card: 3},
+		keylessTestRow{c0: 2, c1: 20, card: 1},
+		keylessTestRow{c0: 3, c1: 30, card: 2},
+	)
+
+	t.Run("cardinality is preserved", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		defer rdr.Close(ctx)
+
+		data, err := ioutil.ReadAll(NewRowEncoder(ctx, rdr))
+		require.NoError(t, err)
+
+		dec := NewRowDecoder(bytes.NewReader(data), sch, vrw)
+		counts := make(map[int64]uint64)
+		for {
+			r, card, err := dec.ReadRowWithCardinality(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			c0, ok := r.GetColVal(keylessC0Tag)
+			require.True(t, ok)
+			counts[int64(c0.(types.Int))] += card
+		}
+		assert.Equal(t, map[int64]uint64{1: 3, 2: 1, 3: 2}, counts)
+	})
+
+	t.Run("read row returns every copy", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		defer rdr.Close(ctx)
+
+		dec := NewRowDecoder(NewRowEncoder(ctx, rdr), sch, vrw)
+		assert.Equal(t, map[int64]uint64{1: 3, 2: 1, 3: 2}, readKeylessMultiset(t, dec))
+	})
+}
+
+func TestRowEncoderTagOrder(t *testing.T) {
+	ctx := context.Background()
+
+	// enough columns that iterating a row's values in map order would almost never give the same order twice
+	const numCols = 32
+	var cols []schema.Column
+	tv := make(row.TaggedValues, numCols)
+	for tag := uint64(0); tag < numCols; tag++ {
+		cols = append(cols, schema.NewColumn(fmt.Sprintf("c%d", tag), tag, types.IntKind, tag == 0))
+		tv[tag] = types.Int(int64(tag) * 7)
+	}
+	coll, err := schema.NewColCollection(cols...)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(coll)
+
+	r, err := row.New(types.Format_Default, sch, tv)
+	require.NoError(t, err)
+
+	t.Run("tags are encoded in ascending order", func(t *testing.T) {
+		tpl, err := taggedValuesTuple(r)
+		require.NoError(t, err)
+		vals, err := tpl.AsSlice()
+		require.NoError(t, err)
+		require.Len(t, vals, 2*numCols)
+
+		for i := 0; i < len(vals); i += 2 {
+			assert.Equal(t, types.Uint(i/2), vals[i])
+			assert.Equal(t, tv[uint64(i/2)], vals[i+1])
+		}
+	})
+
+	t.Run("the same row always encodes to the same bytes", func(t *testing.T) {
+		encode := func() []byte {
+			rdr := NewInMemTableReader(NewInMemTableWithData(sch, []row.Row{r}))
+			data, err := ioutil.ReadAll(NewRowEncoder(ctx, rdr))
+			require.NoError(t, err)
+			return data
+		}
+
+		expected := encode()
+		for i := 0; i < 20; i++ {
+			assert.Equal(t, expected, encode())
+		}
+	})
+}
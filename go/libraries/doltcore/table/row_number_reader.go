@@ -0,0 +1,118 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// RowNumberReader is a SqlTableReader which adds a column holding the number of each row to the rows of another
+// reader. Rows are numbered consecutively in the order they are read, starting from a given number. Every copy of a
+// keyless row is numbered separately, so each copy is returned as a distinct row with a cardinality of 1.
+type RowNumberReader struct {
+	SqlTableReader
+	sch  schema.Schema
+	tag  uint64
+	next int64
+	conv *row.SqlRowConverter
+}
+
+var _ SqlTableReader = &RowNumberReader{}
+
+// NewRowNumberReader creates a RowNumberReader which adds a non-nullable BIGINT column named |name| with the tag |tag|
+// to the rows of |rdr|, after its other columns. The first row read is numbered |start|. Returns an error if the
+// schema of |rdr| already has a column with that name or tag.
+func NewRowNumberReader(rdr SqlTableReader, name string, tag uint64, start int64) (*RowNumberReader, error) {
+	cols := rdr.GetSchema().GetAllCols()
+	if _, ok := cols.GetByNameCaseInsensitive(name); ok {
+		return nil, fmt.Errorf("cannot add row number column '%s', a column with that name already exists", name)
+	}
+	if _, ok := cols.GetByTag(tag); ok {
+		return nil, fmt.Errorf("cannot add row number column '%s', a column with tag %d already exists", name, tag)
+	}
+
+	numCol := schema.NewColumn(name, tag, types.IntKind, false, schema.NotNullConstraint{})
+	allCols, err := cols.Append(numCol)
+	if err != nil {
+		return nil, err
+	}
+
+	sch, err := schema.SchemaFromCols(allCols)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RowNumberReader{
+		SqlTableReader: rdr,
+		sch:            sch,
+		tag:            tag,
+		next:           start,
+		conv:           row.NewSqlRowConverter(sch),
+	}, nil
+}
+
+// GetSchema returns the schema of the underlying reader with the row number column added.
+func (rd *RowNumberReader) GetSchema() schema.Schema {
+	return rd.sch
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *RowNumberReader) ReadRow(ctx context.Context) (row.Row, error) {
+	r, err := rd.SqlTableReader.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tv, err := row.GetTaggedVals(r)
+	if err != nil {
+		return nil, err
+	}
+	tv[rd.tag] = types.Int(rd.next)
+
+	if schema.IsKeyless(rd.sch) {
+		r, err = row.NewKeylessRow(r.Format(), rd.sch, tv, 1)
+	} else {
+		r, err = row.New(r.Format(), rd.sch, tv)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rd.next++
+
+	return r, nil
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *RowNumberReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.conv.Convert(r)
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *RowNumberReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
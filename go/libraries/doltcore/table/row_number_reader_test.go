@@ -0,0 +1,94 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+func TestRowNumberReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	const rowNumTag = 100
+
+	t.Run("keyless", func(t *testing.T) {
+		sch := mustKeylessSchema(t)
+		tbl := newKeylessTestTable(t, sch,
+			keylessTestRow{c0: 0, c1: 0, card: 2},
+			keylessTestRow{c0: 1, c1: 1, card: 1},
+			keylessTestRow{c0: 2, c1: 2, card: 3},
+		)
+
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		numRdr, err := NewRowNumberReader(rdr, "row_num", rowNumTag, 10)
+		require.NoError(t, err)
+		defer numRdr.Close(ctx)
+
+		outSch := numRdr.GetSchema()
+		assert.True(t, schema.IsKeyless(outSch))
+		assert.Equal(t, []uint64{keylessC0Tag, keylessC1Tag, rowNumTag}, outSch.GetAllCols().Tags)
+
+		var nums []int64
+		counts := make(map[int64]int)
+		for {
+			r, err := numRdr.ReadSqlRow(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			require.Len(t, r, 3)
+
+			counts[r[0].(int64)]++
+			nums = append(nums, r[2].(int64))
+		}
+
+		assert.Equal(t, []int64{10, 11, 12, 13, 14, 15}, nums)
+		assert.Equal(t, map[int64]int{0: 2, 1: 1, 2: 3}, counts)
+	})
+
+	t.Run("keyed", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, newKeyedTestTable(t, 3))
+		require.NoError(t, err)
+		numRdr, err := NewRowNumberReader(rdr, "row_num", rowNumTag, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, []sql.Row{
+			{int64(0), int64(0), int64(1)},
+			{int64(1), int64(10), int64(2)},
+			{int64(2), int64(20), int64(3)},
+		}, readAllSqlRows(t, numRdr))
+	})
+
+	t.Run("column collisions", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, newKeyedTestTable(t, 3))
+		require.NoError(t, err)
+
+		_, err = NewRowNumberReader(rdr, "VAL", rowNumTag, 0)
+		assert.Error(t, err)
+		_, err = NewRowNumberReader(rdr, "row_num", 1, 0)
+		assert.Error(t, err)
+	})
+}
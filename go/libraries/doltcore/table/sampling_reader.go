@@ -0,0 +1,88 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+)
+
+// SamplingReader is a SqlTableReader which returns a random sample of the rows of another reader. Each row is kept
+// with a fixed probability, independently of the others, so the number of rows returned is only approximately the
+// requested fraction of the rows read. Every copy of a keyless row is sampled separately. The sample is determined by
+// a seed, so readers created with the same seed over the same rows return the same sample.
+type SamplingReader struct {
+	SqlTableReader
+	fraction float64
+	rng      *rand.Rand
+}
+
+var _ SqlTableReader = &SamplingReader{}
+
+// NewSamplingReader creates a SamplingReader which keeps each row of |rdr| with the probability |fraction|, which
+// must be between 0 and 1, using random numbers generated from |seed|.
+func NewSamplingReader(rdr SqlTableReader, fraction float64, seed int64) (*SamplingReader, error) {
+	if fraction < 0 || fraction > 1 {
+		return nil, fmt.Errorf("invalid sample fraction %v, must be between 0 and 1", fraction)
+	}
+
+	return &SamplingReader{
+		SqlTableReader: rdr,
+		fraction:       fraction,
+		rng:            rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *SamplingReader) ReadRow(ctx context.Context) (row.Row, error) {
+	for {
+		r, err := rd.SqlTableReader.ReadRow(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if rd.keep() {
+			return r, nil
+		}
+	}
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *SamplingReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	for {
+		r, err := rd.SqlTableReader.ReadSqlRow(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if rd.keep() {
+			return r, nil
+		}
+	}
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *SamplingReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
+
+func (rd *SamplingReader) keep() bool {
+	return rd.rng.Float64() < rd.fraction
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+func TestSamplingReader(t *testing.T) {
+	ctx := context.Background()
+	const numRows = 10000
+
+	tbl := newKeyedTestTable(t, numRows)
+	sample := func(t *testing.T, fraction float64, seed int64) []int64 {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		sr, err := NewSamplingReader(rdr, fraction, seed)
+		require.NoError(t, err)
+		defer sr.Close(ctx)
+
+		var pks []int64
+		for _, r := range readAllSqlRows(t, sr) {
+			pks = append(pks, r[0].(int64))
+		}
+		return pks
+	}
+
+	t.Run("same seed", func(t *testing.T) {
+		assert.Equal(t, sample(t, 0.1, 42), sample(t, 0.1, 42))
+		assert.NotEqual(t, sample(t, 0.1, 42), sample(t, 0.1, 43))
+	})
+
+	t.Run("sample size", func(t *testing.T) {
+		for _, fraction := range []float64{0.01, 0.1, 0.5} {
+			// allow for 4 standard deviations of the binomial distribution
+			expected := fraction * numRows
+			assert.InDelta(t, expected, len(sample(t, fraction, 7)), 4*math.Sqrt(expected*(1-fraction)))
+		}
+		assert.Empty(t, sample(t, 0, 7))
+		assert.Len(t, sample(t, 1, 7), numRows)
+	})
+
+	t.Run("keyless copies are sampled separately", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		sch := mustKeylessSchema(t)
+		keyless := newKeylessTestTable(t, sch,
+			keylessTestRow{c0: 0, c1: 0, card: numRows},
+			keylessTestRow{c0: 1, c1: 1, card: 1},
+		)
+
+		rdr, err := NewTableReader(ctx, keyless)
+		require.NoError(t, err)
+		sr, err := NewSamplingReader(rdr, 0.25, 1)
+		require.NoError(t, err)
+
+		counts := readKeylessMultiset(t, sr)
+		assert.InDelta(t, 0.25*numRows, counts[0], 4*math.Sqrt(0.25*0.75*numRows))
+	})
+
+	t.Run("invalid fraction", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		_, err = NewSamplingReader(rdr, 1.5, 0)
+		assert.Error(t, err)
+		_, err = NewSamplingReader(rdr, -0.1, 0)
+		assert.Error(t, err)
+	})
+}
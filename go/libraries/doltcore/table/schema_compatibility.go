@@ -0,0 +1,86 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+)
+
+// SchemasCompatible returns whether rows read with the schema |src| can be written to a table with the schema |dst|
+// without losing values or violating constraints, along with a reason for each column that prevents it. Columns are
+// matched by tag, as they are when rows are copied between tables. Source columns may have a narrower type than their
+// destination column, such as an INT column written to a BIGINT column, but not a wider or different type.
+func SchemasCompatible(src, dst schema.Schema) (bool, []string) {
+	srcCols := src.GetAllCols()
+	dstCols := dst.GetAllCols()
+
+	var reasons []string
+	_ = dstCols.Iter(func(tag uint64, dstCol schema.Column) (stop bool, err error) {
+		srcCol, ok := srcCols.GetByTag(tag)
+		if !ok {
+			if !dstCol.IsNullable() && dstCol.Default == "" {
+				reasons = append(reasons, fmt.Sprintf("column '%s' is missing from the source and cannot be null", dstCol.Name))
+			}
+			return false, nil
+		}
+
+		if !typeFits(srcCol.TypeInfo, dstCol.TypeInfo) {
+			reasons = append(reasons, fmt.Sprintf("column '%s' has type %s in the source, which does not fit in type %s",
+				dstCol.Name, srcCol.TypeInfo.String(), dstCol.TypeInfo.String()))
+		}
+
+		if srcCol.IsNullable() && !dstCol.IsNullable() {
+			reasons = append(reasons, fmt.Sprintf("column '%s' is nullable in the source but not in the destination", dstCol.Name))
+		}
+
+		return false, nil
+	})
+
+	_ = srcCols.Iter(func(tag uint64, srcCol schema.Column) (stop bool, err error) {
+		if _, ok := dstCols.GetByTag(tag); !ok {
+			reasons = append(reasons, fmt.Sprintf("column '%s' does not exist in the destination", srcCol.Name))
+		}
+		return false, nil
+	})
+
+	return len(reasons) == 0, reasons
+}
+
+// typeFits returns whether every value of the type |src| is also a value of the type |dst|.
+func typeFits(src, dst typeinfo.TypeInfo) bool {
+	if src.Equals(dst) {
+		return true
+	}
+
+	if src.GetTypeIdentifier() != dst.GetTypeIdentifier() {
+		return false
+	}
+
+	switch dstType := dst.ToSqlType().(type) {
+	case sql.StringType:
+		srcType, ok := src.ToSqlType().(sql.StringType)
+		return ok && srcType.Collation() == dstType.Collation() && srcType.MaxCharacterLength() <= dstType.MaxCharacterLength()
+	case sql.NumberType:
+		// the query types of a signed, unsigned or floating point family are ordered by their size
+		return src.ToSqlType().Type() <= dstType.Type()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+)
+
+func TestSchemasCompatible(t *testing.T) {
+	varchar := func(length int64) typeinfo.TypeInfo {
+		ti, err := typeinfo.FromSqlType(sql.MustCreateStringWithDefaults(sqltypes.VarChar, length))
+		require.NoError(t, err)
+		return ti
+	}
+
+	col := func(name string, tag uint64, ti typeinfo.TypeInfo, pk bool, constraints ...schema.ColConstraint) schema.Column {
+		c, err := schema.NewColumnWithTypeInfo(name, tag, ti, pk, "", false, "", constraints...)
+		require.NoError(t, err)
+		return c
+	}
+
+	sch := func(cols ...schema.Column) schema.Schema {
+		coll, err := schema.NewColCollection(cols...)
+		require.NoError(t, err)
+		return schema.MustSchemaFromCols(coll)
+	}
+
+	typeReason := func(name string, src, dst typeinfo.TypeInfo) string {
+		return fmt.Sprintf("column '%s' has type %s in the source, which does not fit in type %s", name, src.String(), dst.String())
+	}
+
+	notNull := schema.NotNullConstraint{}
+	pk := col("pk", 0, typeinfo.Int64Type, true, notNull)
+
+	tests := []struct {
+		name       string
+		src, dst   schema.Schema
+		compatible bool
+		reasons    []string
+	}{
+		{
+			name:       "identical",
+			src:        sch(pk, col("name", 1, varchar(20), false)),
+			dst:        sch(pk, col("name", 1, varchar(20), false)),
+			compatible: true,
+		},
+		{
+			name: "widened types",
+			src: sch(pk,
+				col("a", 1, typeinfo.Int32Type, false),
+				col("b", 2, typeinfo.Float32Type, false),
+				col("c", 3, varchar(10), false),
+			),
+			dst: sch(pk,
+				col("a", 1, typeinfo.Int64Type, false),
+				col("b", 2, typeinfo.Float64Type, false),
+				col("c", 3, varchar(20), false),
+			),
+			compatible: true,
+		},
+		{
+			name:       "nullable column missing from source",
+			src:        sch(pk),
+			dst:        sch(pk, col("a", 1, typeinfo.Int64Type, false)),
+			compatible: true,
+		},
+		{
+			name: "narrowed types",
+			src: sch(pk,
+				col("a", 1, typeinfo.Int64Type, false),
+				col("c", 3, varchar(20), false),
+			),
+			dst: sch(pk,
+				col("a", 1, typeinfo.Int32Type, false),
+				col("c", 3, varchar(10), false),
+			),
+			reasons: []string{
+				typeReason("a", typeinfo.Int64Type, typeinfo.Int32Type),
+				typeReason("c", varchar(20), varchar(10)),
+			},
+		},
+		{
+			name:    "different types",
+			src:     sch(pk, col("a", 1, typeinfo.Int64Type, false)),
+			dst:     sch(pk, col("a", 1, typeinfo.Uint64Type, false)),
+			reasons: []string{typeReason("a", typeinfo.Int64Type, typeinfo.Uint64Type)},
+		},
+		{
+			name:    "not null tightening",
+			src:     sch(pk, col("a", 1, typeinfo.Int64Type, false)),
+			dst:     sch(pk, col("a", 1, typeinfo.Int64Type, false, notNull)),
+			reasons: []string{"column 'a' is nullable in the source but not in the destination"},
+		},
+		{
+			name:    "not null column missing from source",
+			src:     sch(pk),
+			dst:     sch(pk, col("a", 1, typeinfo.Int64Type, false, notNull)),
+			reasons: []string{"column 'a' is missing from the source and cannot be null"},
+		},
+		{
+			name:    "column missing from destination",
+			src:     sch(pk, col("a", 1, typeinfo.Int64Type, false)),
+			dst:     sch(pk),
+			reasons: []string{"column 'a' does not exist in the destination"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			compatible, reasons := SchemasCompatible(test.src, test.dst)
+			assert.Equal(t, test.compatible, compatible)
+			assert.Equal(t, test.reasons, reasons)
+		})
+	}
+}
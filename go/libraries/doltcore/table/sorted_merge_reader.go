@@ -0,0 +1,232 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrUnsortedMergeInput is returned by a SortedMergeReader when one of its readers returns rows that are not sorted
+// by the reader's join column.
+var ErrUnsortedMergeInput = errors.New("sorted merge reader input is not sorted by its join column")
+
+// CardinalityRow is a row along with the number of copies of it.
+type CardinalityRow struct {
+	Row  row.Row
+	Card uint64
+}
+
+// MergeGroup holds the rows of the two readers of a SortedMergeReader that have the same join column value.
+type MergeGroup struct {
+	// Key is the join column value shared by every row in the group.
+	Key   types.Value
+	Left  []CardinalityRow
+	Right []CardinalityRow
+}
+
+// Pairs returns the number of pairs of left and right rows in the group, counting every copy of a row.
+func (g MergeGroup) Pairs() uint64 {
+	var left, right uint64
+	for _, r := range g.Left {
+		left += r.Card
+	}
+	for _, r := range g.Right {
+		right += r.Card
+	}
+
+	return left * right
+}
+
+// SortedMergeReader merges two readers which are each sorted in ascending order by a join column, and returns the
+// groups of rows whose join column values are equal on both sides, as a merge join would. Join column values that
+// only occur on one side are skipped, as are rows whose join column is NULL, which never match. Readers which
+// implement ReadRowWithCardinality, such as keyless table readers and readers created with WithOrderBy, are read one
+// physical row at a time and the copies of a row are kept together. Each row read from other readers has a
+// cardinality of 1.
+type SortedMergeReader struct {
+	left, right *mergeSide
+}
+
+// NewSortedMergeReader creates a SortedMergeReader which joins the rows of |left| on the column with the tag
+// |leftTag| to the rows of |right| on the column with the tag |rightTag|.
+func NewSortedMergeReader(left, right SqlTableReader, leftTag, rightTag uint64) (*SortedMergeReader, error) {
+	l, err := newMergeSide(left, leftTag)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := newMergeSide(right, rightTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SortedMergeReader{left: l, right: r}, nil
+}
+
+// NextGroup returns the next group of rows with equal join column values, in ascending order of those values, or
+// io.EOF once there are no more matches.
+func (rd *SortedMergeReader) NextGroup(ctx context.Context) (MergeGroup, error) {
+	for {
+		lKey, err := rd.left.peek(ctx)
+		if err != nil {
+			return MergeGroup{}, err
+		}
+
+		rKey, err := rd.right.peek(ctx)
+		if err != nil {
+			return MergeGroup{}, err
+		}
+
+		cmp, err := compareJoinValues(rd.left.next.Row.Format(), lKey, rKey)
+		if err != nil {
+			return MergeGroup{}, err
+		}
+
+		if cmp < 0 {
+			rd.left.advance()
+		} else if cmp > 0 {
+			rd.right.advance()
+		} else {
+			group := MergeGroup{Key: lKey}
+			if group.Left, err = rd.left.readGroup(ctx, lKey); err != nil {
+				return MergeGroup{}, err
+			}
+			if group.Right, err = rd.right.readGroup(ctx, rKey); err != nil {
+				return MergeGroup{}, err
+			}
+
+			return group, nil
+		}
+	}
+}
+
+// Close closes both of the underlying readers if they can be closed.
+func (rd *SortedMergeReader) Close(ctx context.Context) error {
+	lErr := closeReader(ctx, rd.left.rdr)
+	rErr := closeReader(ctx, rd.right.rdr)
+	if lErr != nil {
+		return lErr
+	}
+
+	return rErr
+}
+
+// mergeSide reads the rows of one reader of a SortedMergeReader, skipping rows with NULL join column values.
+type mergeSide struct {
+	rdr     SqlTableReader
+	cardRdr cardinalityReader
+	tag     uint64
+
+	// next is the row read but not yet consumed, and key is its join column value.
+	next    *CardinalityRow
+	key     types.Value
+	prevKey types.Value
+}
+
+func newMergeSide(rdr SqlTableReader, tag uint64) (*mergeSide, error) {
+	if _, ok := rdr.GetSchema().GetAllCols().GetByTag(tag); !ok {
+		return nil, fmt.Errorf("cannot merge on unknown column tag %d", tag)
+	}
+
+	cardRdr, _ := rdr.(cardinalityReader)
+
+	return &mergeSide{rdr: rdr, cardRdr: cardRdr, tag: tag}, nil
+}
+
+// peek reads the next row with a non-NULL join column value if it hasn't been read yet, and returns its join
+// column value.
+func (s *mergeSide) peek(ctx context.Context) (types.Value, error) {
+	for s.next == nil {
+		var r row.Row
+		card := uint64(1)
+		var err error
+		if s.cardRdr != nil {
+			r, card, err = s.cardRdr.ReadRowWithCardinality(ctx)
+		} else {
+			r, err = s.rdr.ReadRow(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		key, _ := r.GetColVal(s.tag)
+		if types.IsNull(key) {
+			continue
+		}
+
+		if s.prevKey != nil {
+			cmp, err := compareJoinValues(r.Format(), s.prevKey, key)
+			if err != nil {
+				return nil, err
+			} else if cmp > 0 {
+				return nil, ErrUnsortedMergeInput
+			}
+		}
+
+		s.next = &CardinalityRow{Row: r, Card: card}
+		s.key = key
+		s.prevKey = key
+	}
+
+	return s.key, nil
+}
+
+// advance consumes the row returned by the last call to peek.
+func (s *mergeSide) advance() {
+	s.next = nil
+	s.key = nil
+}
+
+// readGroup consumes and returns every row whose join column value equals |key|, starting with the peeked row.
+func (s *mergeSide) readGroup(ctx context.Context, key types.Value) ([]CardinalityRow, error) {
+	var rows []CardinalityRow
+	for {
+		k, err := s.peek(ctx)
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if !k.Equals(key) {
+			return rows, nil
+		}
+
+		rows = append(rows, *s.next)
+		s.advance()
+	}
+}
+
+// compareJoinValues compares two non-NULL join column values.
+func compareJoinValues(nbf *types.NomsBinFormat, left, right types.Value) (int, error) {
+	if left.Equals(right) {
+		return 0, nil
+	}
+
+	less, err := left.Less(nbf, right)
+	if err != nil {
+		return 0, err
+	} else if less {
+		return -1, nil
+	}
+
+	return 1, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestSortedMergeReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+
+	// mergeResult summarizes a MergeGroup by its key and the number of copies of rows on each side
+	type mergeResult struct {
+		key         int64
+		left, right uint64
+	}
+
+	tests := []struct {
+		name        string
+		left, right []keylessTestRow
+		expected    []mergeResult
+	}{
+		{
+			name: "overlapping keys",
+			left: []keylessTestRow{
+				{c0: 0, c1: 1, card: 2},
+				{c0: 1, c1: 2, card: 1},
+				{c0: 2, c1: 3, card: 1},
+			},
+			right: []keylessTestRow{
+				{c0: 0, c1: 2, card: 3},
+				{c0: 1, c1: 3, card: 1},
+				{c0: 2, c1: 4, card: 1},
+			},
+			expected: []mergeResult{
+				{key: 2, left: 1, right: 3},
+				{key: 3, left: 1, right: 1},
+			},
+		},
+		{
+			name: "disjoint keys",
+			left: []keylessTestRow{
+				{c0: 0, c1: 1, card: 1},
+				{c0: 1, c1: 2, card: 2},
+			},
+			right: []keylessTestRow{
+				{c0: 0, c1: 3, card: 1},
+				{c0: 1, c1: 4, card: 2},
+			},
+		},
+		{
+			name: "interleaved keys",
+			left: []keylessTestRow{
+				{c0: 0, c1: 1, card: 1},
+				{c0: 1, c1: 3, card: 1},
+				{c0: 2, c1: 5, card: 1},
+			},
+			right: []keylessTestRow{
+				{c0: 0, c1: 2, card: 1},
+				{c0: 1, c1: 3, card: 1},
+				{c0: 2, c1: 4, card: 1},
+			},
+			expected: []mergeResult{
+				{key: 3, left: 1, right: 1},
+			},
+		},
+		{
+			name: "multiple rows and copies on each side",
+			left: []keylessTestRow{
+				{c0: 0, c1: 5, card: 2},
+				{c0: 1, c1: 5, card: 1},
+				{c0: 2, c1: 6, card: 4},
+			},
+			right: []keylessTestRow{
+				{c0: 0, c1: 5, card: 3},
+				{c0: 1, c1: 6, card: 2},
+				{c0: 2, c1: 6, card: 1},
+			},
+			expected: []mergeResult{
+				{key: 5, left: 3, right: 3},
+				{key: 6, left: 4, right: 3},
+			},
+		},
+		{
+			name:  "empty side",
+			left:  []keylessTestRow{{c0: 0, c1: 1, card: 1}},
+			right: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			left, err := NewTableReader(ctx, newKeylessTestTable(t, sch, test.left...), WithOrderBy(keylessC1Tag))
+			require.NoError(t, err)
+			right, err := NewTableReader(ctx, newKeylessTestTable(t, sch, test.right...), WithOrderBy(keylessC1Tag))
+			require.NoError(t, err)
+
+			rdr, err := NewSortedMergeReader(left, right, keylessC1Tag, keylessC1Tag)
+			require.NoError(t, err)
+			defer rdr.Close(ctx)
+
+			var actual []mergeResult
+			for {
+				group, err := rdr.NextGroup(ctx)
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+
+				res := mergeResult{key: int64(group.Key.(types.Int))}
+				for _, r := range group.Left {
+					res.left += r.Card
+				}
+				for _, r := range group.Right {
+					res.right += r.Card
+				}
+				assert.Equal(t, res.left*res.right, group.Pairs())
+
+				actual = append(actual, res)
+			}
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	t.Run("unsorted input", func(t *testing.T) {
+		left := newKeylessTestTable(t, sch, keylessTestRow{c0: 0, c1: 5, card: 1})
+		right := newKeylessTestTable(t, sch,
+			keylessTestRow{c0: 0, c1: 2, card: 1},
+			keylessTestRow{c0: 1, c1: 1, card: 1},
+			keylessTestRow{c0: 2, c1: 3, card: 1},
+		)
+
+		lRdr, err := NewTableReader(ctx, left, WithOrderBy(keylessC1Tag))
+		require.NoError(t, err)
+		rRdr, err := NewTableReader(ctx, right, WithOrderBy(keylessC0Tag))
+		require.NoError(t, err)
+
+		rdr, err := NewSortedMergeReader(lRdr, rRdr, keylessC1Tag, keylessC1Tag)
+		require.NoError(t, err)
+
+		for err == nil {
+			_, err = rdr.NextGroup(ctx)
+		}
+		assert.Equal(t, ErrUnsortedMergeInput, err)
+	})
+}
@@ -0,0 +1,197 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// cardinalityReader is implemented by readers which can return a physical row along with its number of copies.
+type cardinalityReader interface {
+	ReadRowWithCardinality(ctx context.Context) (row.Row, uint64, error)
+}
+
+var _ cardinalityReader = &keylessTableReader{}
+
+type sortedRow struct {
+	r    row.Row
+	card uint64
+}
+
+// sortedTableReader buffers all the rows of a SqlTableReader and returns them sorted by a set of columns. The
+// copies of a keyless row are buffered as a single row with a cardinality, and are only expanded after sorting.
+type sortedTableReader struct {
+	SqlTableReader
+	tags []uint64
+
+	rows   []sortedRow
+	loaded bool
+	idx    int
+
+	// curr is the row being returned, and duplicates is the number of copies of it left to return.
+	curr       row.Row
+	duplicates uint64
+}
+
+var _ SqlTableReader = &sortedTableReader{}
+var _ cardinalityReader = &sortedTableReader{}
+
+func newSortedTableReader(rdr SqlTableReader, tags []uint64) (*sortedTableReader, error) {
+	allCols := rdr.GetSchema().GetAllCols()
+	for _, tag := range tags {
+		if _, ok := allCols.GetByTag(tag); !ok {
+			return nil, fmt.Errorf("cannot order by unknown column tag %d", tag)
+		}
+	}
+
+	return &sortedTableReader{SqlTableReader: rdr, tags: tags}, nil
+}
+
+// ReadRow implements the TableReader interface.
+func (rdr *sortedTableReader) ReadRow(ctx context.Context) (row.Row, error) {
+	if !rdr.loaded {
+		if err := rdr.load(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if rdr.duplicates == 0 {
+		if rdr.idx >= len(rdr.rows) {
+			return nil, io.EOF
+		}
+
+		rdr.curr, rdr.duplicates = rdr.rows[rdr.idx].r, rdr.rows[rdr.idx].card
+		rdr.idx++
+	}
+
+	rdr.duplicates -= 1
+
+	return rdr.curr, nil
+}
+
+// ReadRowWithCardinality reads a row along with the number of copies of it, and advances past that row. If some
+// copies of the current row were already returned by ReadRow, only the remaining copies are counted.
+func (rdr *sortedTableReader) ReadRowWithCardinality(ctx context.Context) (row.Row, uint64, error) {
+	r, err := rdr.ReadRow(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	card := rdr.duplicates + 1
+	rdr.duplicates = 0
+
+	return r, card, nil
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rdr *sortedTableReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rdr.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return row.DoltRowToSqlRow(r, rdr.GetSchema())
+}
+
+// Close releases the buffered rows and closes the underlying reader if it can be closed.
+func (rdr *sortedTableReader) Close(ctx context.Context) error {
+	rdr.rows = nil
+	rdr.curr = nil
+	rdr.duplicates = 0
+
+	return closeReader(ctx, rdr.SqlTableReader)
+}
+
+// load reads every row of the underlying reader and sorts them.
+func (rdr *sortedTableReader) load(ctx context.Context) error {
+	cardRdr, hasCard := rdr.SqlTableReader.(cardinalityReader)
+
+	var rows []sortedRow
+	for {
+		var r row.Row
+		card := uint64(1)
+		var err error
+		if hasCard {
+			r, card, err = cardRdr.ReadRowWithCardinality(ctx)
+		} else {
+			r, err = rdr.SqlTableReader.ReadRow(ctx)
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		rows = append(rows, sortedRow{r: r, card: card})
+	}
+
+	var sortErr error
+	sort.SliceStable(rows, func(i, j int) bool {
+		cmp, err := compareRowsByTags(rows[i].r, rows[j].r, rdr.tags)
+		if err != nil && sortErr == nil {
+			sortErr = err
+		}
+		return cmp < 0
+	})
+	if sortErr != nil {
+		return sortErr
+	}
+
+	rdr.rows = rows
+	rdr.loaded = true
+
+	return nil
+}
+
+// compareRowsByTags compares the values of |tags| in two rows, in order, treating NULL as less than any other value.
+func compareRowsByTags(left, right row.Row, tags []uint64) (int, error) {
+	for _, tag := range tags {
+		lv, _ := left.GetColVal(tag)
+		rv, _ := right.GetColVal(tag)
+
+		lNull, rNull := types.IsNull(lv), types.IsNull(rv)
+		if lNull && rNull {
+			continue
+		} else if lNull {
+			return -1, nil
+		} else if rNull {
+			return 1, nil
+		}
+
+		if lv.Equals(rv) {
+			continue
+		}
+
+		less, err := lv.Less(left.Format(), rv)
+		if err != nil {
+			return 0, err
+		} else if less {
+			return -1, nil
+		}
+
+		return 1, nil
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+func TestSortedTableReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTable(t, sch,
+		keylessTestRow{c0: 0, c1: 30, card: 1},
+		keylessTestRow{c0: 1, c1: 10, card: 2},
+		keylessTestRow{c0: 2, c1: 20, card: 1},
+		keylessTestRow{c0: 3, c1: 10, card: 3},
+		keylessTestRow{c0: 4, c1: 0, card: 1},
+	)
+
+	readSqlRows := func(t *testing.T, opts ...ReaderOption) [][2]int64 {
+		rdr, err := NewTableReader(ctx, tbl, opts...)
+		require.NoError(t, err)
+
+		var rows [][2]int64
+		for _, r := range readAllSqlRows(t, rdr) {
+			rows = append(rows, [2]int64{r[0].(int64), r[1].(int64)})
+		}
+		return rows
+	}
+
+	t.Run("order by non-key column", func(t *testing.T) {
+		expected := [][2]int64{{4, 0}, {1, 10}, {1, 10}, {3, 10}, {3, 10}, {3, 10}, {2, 20}, {0, 30}}
+		assert.Equal(t, expected, readSqlRows(t, WithOrderBy(keylessC1Tag, keylessC0Tag)))
+	})
+
+	t.Run("copies stay grouped", func(t *testing.T) {
+		rows := readSqlRows(t, WithOrderBy(keylessC1Tag))
+		require.Len(t, rows, 8)
+
+		// rows (1, 10) and (3, 10) tie on c1, so their relative order follows the map, but each row's copies are
+		// returned together
+		assert.Equal(t, [2]int64{4, 0}, rows[0])
+		tied := rows[1:6]
+		if tied[0][0] == 1 {
+			assert.Equal(t, [][2]int64{{1, 10}, {1, 10}, {3, 10}, {3, 10}, {3, 10}}, tied)
+		} else {
+			assert.Equal(t, [][2]int64{{3, 10}, {3, 10}, {3, 10}, {1, 10}, {1, 10}}, tied)
+		}
+		assert.Equal(t, [][2]int64{{2, 20}, {0, 30}}, rows[6:])
+
+		// the order is deterministic
+		assert.Equal(t, rows, readSqlRows(t, WithOrderBy(keylessC1Tag)))
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		_, err := NewTableReader(ctx, tbl, WithOrderBy(42))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// SparseRow holds the non-null columns of a row, in schema order. Values[i] is the value of the column with the tag
+// Tags[i].
+type SparseRow struct {
+	Tags   []uint64
+	Values sql.Row
+}
+
+// SparseReader is a SqlTableReader which can also read rows without their null columns, for consumers of wide,
+// sparse tables that would otherwise receive mostly nulls. ReadRow and ReadSqlRow are unchanged and still return
+// rows with one field per column of the schema. Only ReadSparseRow omits null columns, so the rows it returns have
+// varying widths.
+type SparseReader struct {
+	SqlTableReader
+	cols *schema.ColCollection
+}
+
+var _ SqlTableReader = &SparseReader{}
+
+// NewSparseReader creates a SparseReader that reads rows from |rdr|.
+func NewSparseReader(rdr SqlTableReader) *SparseReader {
+	return &SparseReader{SqlTableReader: rdr, cols: rdr.GetSchema().GetAllCols()}
+}
+
+// ReadSparseRow reads the next row, returning only the columns that are not null. Null columns are never converted
+// to sql values.
+func (rd *SparseReader) ReadSparseRow(ctx context.Context) (SparseRow, error) {
+	r, err := rd.SqlTableReader.ReadRow(ctx)
+	if err != nil {
+		return SparseRow{}, err
+	}
+
+	var tags []uint64
+	var vals []types.Value
+	_, err = r.IterCols(func(tag uint64, val types.Value) (stop bool, err error) {
+		if _, ok := rd.cols.TagToIdx[tag]; ok && !types.IsNull(val) {
+			tags = append(tags, tag)
+			vals = append(vals, val)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return SparseRow{}, err
+	}
+
+	sort.Sort(byColIdx{tags: tags, vals: vals, tagToIdx: rd.cols.TagToIdx})
+
+	sr := SparseRow{Tags: tags, Values: make(sql.Row, len(vals))}
+	for i, tag := range tags {
+		sr.Values[i], err = rd.cols.TagToCol[tag].TypeInfo.ConvertNomsValueToValue(vals[i])
+		if err != nil {
+			return SparseRow{}, err
+		}
+	}
+
+	return sr, nil
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *SparseReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
+
+// byColIdx sorts tags and their values by the index of the tag's column in the schema.
+type byColIdx struct {
+	tags     []uint64
+	vals     []types.Value
+	tagToIdx map[uint64]int
+}
+
+func (b byColIdx) Len() int {
+	return len(b.tags)
+}
+
+func (b byColIdx) Less(i, j int) bool {
+	return b.tagToIdx[b.tags[i]] < b.tagToIdx[b.tags[j]]
+}
+
+func (b byColIdx) Swap(i, j int) {
+	b.tags[i], b.tags[j] = b.tags[j], b.tags[i]
+	b.vals[i], b.vals[j] = b.vals[j], b.vals[i]
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestSparseReader(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	const numCols = 10
+
+	ctx := context.Background()
+
+	// tags are assigned in reverse so that schema order differs from tag order
+	var cols []schema.Column
+	for i := 0; i < numCols; i++ {
+		cols = append(cols, schema.NewColumn(fmt.Sprintf("c%d", i), uint64(numCols-1-i), types.IntKind, false))
+	}
+	coll, err := schema.NewColCollection(cols...)
+	require.NoError(t, err)
+	sch, err := schema.SchemaFromCols(coll)
+	require.NoError(t, err)
+
+	vrw, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_Default, nil, nil)
+	require.NoError(t, err)
+
+	// every other column is null
+	tv := make(row.TaggedValues)
+	for tag := uint64(0); tag < numCols; tag += 2 {
+		tv[tag] = types.Int(tag)
+	}
+	r, err := row.NewKeylessRow(vrw.Format(), sch, tv, 2)
+	require.NoError(t, err)
+
+	emptyMap, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	rowData, err := emptyMap.Edit().Set(r.NomsMapKey(sch), r.NomsMapValue(sch)).Map(ctx)
+	require.NoError(t, err)
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData, emptyMap)
+	require.NoError(t, err)
+
+	t.Run("sparse rows", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		srdr := NewSparseReader(rdr)
+
+		for i := 0; i < 2; i++ {
+			sr, err := srdr.ReadSparseRow(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []uint64{8, 6, 4, 2, 0}, sr.Tags)
+			assert.Equal(t, sql.Row{int64(8), int64(6), int64(4), int64(2), int64(0)}, sr.Values)
+		}
+
+		_, err = srdr.ReadSparseRow(ctx)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("sql rows keep the schema's width", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		srdr := NewSparseReader(rdr)
+
+		sqlRow, err := srdr.ReadSqlRow(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, sql.Row{nil, int64(8), nil, int64(6), nil, int64(4), nil, int64(2), nil, int64(0)}, sqlRow)
+	})
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/bits"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrStatsIncomplete is returned when a StatsCollector's statistics are requested before every row has been read.
+var ErrStatsIncomplete = errors.New("column statistics are not available until every row has been read")
+
+// ColumnStats holds statistics about the values of a column.
+type ColumnStats struct {
+	// Min and Max are the smallest and largest non-null values of the column, or nil if every value is null.
+	Min, Max types.Value
+	// NullCount is the number of rows in which the column is null.
+	NullCount uint64
+	// DistinctCount is an estimate of the number of distinct non-null values of the column.
+	DistinctCount uint64
+}
+
+// StatsCollector is a SqlTableReader which gathers statistics about the values of each column of the rows read from
+// another reader. Every copy of a keyless row is read and counted, so the statistics of keyless tables are weighted
+// by cardinality. Distinct value counts are estimated with a HyperLogLog sketch, which bounds the memory used for
+// each column regardless of the number of rows.
+type StatsCollector struct {
+	SqlTableReader
+	cols *schema.ColCollection
+	conv *row.SqlRowConverter
+
+	rowCount uint64
+	stats    map[uint64]*columnStatsAcc
+	done     bool
+}
+
+var _ SqlTableReader = &StatsCollector{}
+
+// columnStatsAcc accumulates the statistics of a single column.
+type columnStatsAcc struct {
+	min, max types.Value
+	nonNull  uint64
+	sketch   *hllSketch
+}
+
+// NewStatsCollector creates a StatsCollector which gathers statistics for the columns of rows read from |rdr|.
+func NewStatsCollector(rdr SqlTableReader) *StatsCollector {
+	sch := rdr.GetSchema()
+	cols := sch.GetAllCols()
+
+	stats := make(map[uint64]*columnStatsAcc, cols.Size())
+	for _, tag := range cols.Tags {
+		stats[tag] = &columnStatsAcc{sketch: newHLLSketch()}
+	}
+
+	return &StatsCollector{
+		SqlTableReader: rdr,
+		cols:           cols,
+		conv:           row.NewSqlRowConverter(sch),
+		stats:          stats,
+	}
+}
+
+// ReadRow implements the TableReader interface.
+func (sc *StatsCollector) ReadRow(ctx context.Context) (row.Row, error) {
+	r, err := sc.SqlTableReader.ReadRow(ctx)
+	if err == io.EOF {
+		sc.done = true
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	nbf := r.Format()
+	_, err = r.IterCols(func(tag uint64, val types.Value) (stop bool, err error) {
+		acc, ok := sc.stats[tag]
+		if !ok || types.IsNull(val) {
+			return false, nil
+		}
+
+		return false, acc.add(nbf, val)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sc.rowCount++
+
+	return r, nil
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (sc *StatsCollector) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := sc.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return sc.conv.Convert(r)
+}
+
+// Stats returns the statistics of each column, keyed by column tag. Returns ErrStatsIncomplete if the reader has not
+// yet returned io.EOF.
+func (sc *StatsCollector) Stats() (map[uint64]ColumnStats, error) {
+	if !sc.done {
+		return nil, ErrStatsIncomplete
+	}
+
+	stats := make(map[uint64]ColumnStats, len(sc.stats))
+	for tag, acc := range sc.stats {
+		stats[tag] = ColumnStats{
+			Min:           acc.min,
+			Max:           acc.max,
+			NullCount:     sc.rowCount - acc.nonNull,
+			DistinctCount: acc.sketch.estimate(),
+		}
+	}
+
+	return stats, nil
+}
+
+// Close closes the underlying reader if it can be closed.
+func (sc *StatsCollector) Close(ctx context.Context) error {
+	return closeReader(ctx, sc.SqlTableReader)
+}
+
+func (acc *columnStatsAcc) add(nbf *types.NomsBinFormat, val types.Value) error {
+	acc.nonNull++
+
+	if acc.min == nil {
+		acc.min, acc.max = val, val
+	} else if less, err := val.Less(nbf, acc.min); err != nil {
+		return err
+	} else if less {
+		acc.min = val
+	} else if less, err = acc.max.Less(nbf, val); err != nil {
+		return err
+	} else if less {
+		acc.max = val
+	}
+
+	h, err := val.Hash(nbf)
+	if err != nil {
+		return err
+	}
+	acc.sketch.add(h)
+
+	return nil
+}
+
+// hllPrecision is the number of hash bits used to choose a register of an hllSketch. Sketches have 2^hllPrecision
+// registers and a standard error of about 1.04 / sqrt(2^hllPrecision), or 1.6%.
+const hllPrecision = 12
+
+// hllSketch is a HyperLogLog sketch which estimates the number of distinct hashes added to it.
+type hllSketch struct {
+	registers []uint8
+}
+
+func newHLLSketch() *hllSketch {
+	return &hllSketch{registers: make([]uint8, 1<<hllPrecision)}
+}
+
+func (s *hllSketch) add(h hash.Hash) {
+	x := binary.BigEndian.Uint64(h[:8])
+	idx := x >> (64 - hllPrecision)
+
+	// the rank is the position of the first set bit in the remaining bits
+	rank := uint8(bits.LeadingZeros64(x<<hllPrecision|1<<(hllPrecision-1)) + 1)
+	if rank > s.registers[idx] {
+		s.registers[idx] = rank
+	}
+}
+
+func (s *hllSketch) estimate() uint64 {
+	m := float64(len(s.registers))
+
+	var sum float64
+	var zeros int
+	for _, r := range s.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	alpha := 0.7213 / (1 + 1.079/m)
+	est := alpha * m * m / sum
+
+	// linear counting is more accurate for small cardinalities
+	if est <= 2.5*m && zeros > 0 {
+		est = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(est + 0.5)
+}
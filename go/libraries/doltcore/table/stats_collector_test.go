@@ -0,0 +1,146 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestStatsCollector(t *testing.T) {
+	ctx := context.Background()
+
+	readAll := func(t *testing.T, sc *StatsCollector) {
+		for {
+			_, err := sc.ReadSqlRow(ctx)
+			if err == io.EOF {
+				return
+			}
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("keyed", func(t *testing.T) {
+		rdr, err := NewTableReader(ctx, newKeyedTestTable(t, 10))
+		require.NoError(t, err)
+		sc := NewStatsCollector(rdr)
+
+		_, err = sc.Stats()
+		assert.Equal(t, ErrStatsIncomplete, err)
+
+		readAll(t, sc)
+		stats, err := sc.Stats()
+		require.NoError(t, err)
+
+		assert.Equal(t, map[uint64]ColumnStats{
+			0: {Min: types.Int(0), Max: types.Int(9), NullCount: 0, DistinctCount: 10},
+			1: {Min: types.Int(0), Max: types.Int(90), NullCount: 0, DistinctCount: 10},
+		}, stats)
+	})
+
+	t.Run("keyless", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		sch := mustKeylessSchema(t)
+		tbl := newKeylessTestTable(t, sch,
+			keylessTestRow{c0: 3, c1: -5, card: 2},
+			keylessTestRow{c0: 7, c1: 12, card: 3},
+			keylessTestRow{c0: -1, c1: 12, card: 1},
+		)
+
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		sc := NewStatsCollector(rdr)
+		readAll(t, sc)
+
+		stats, err := sc.Stats()
+		require.NoError(t, err)
+		assert.Equal(t, map[uint64]ColumnStats{
+			keylessC0Tag: {Min: types.Int(-1), Max: types.Int(7), NullCount: 0, DistinctCount: 3},
+			keylessC1Tag: {Min: types.Int(-5), Max: types.Int(12), NullCount: 0, DistinctCount: 2},
+		}, stats)
+	})
+
+	t.Run("nulls", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		sch := mustKeylessSchema(t)
+		vrw, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_Default, nil, nil)
+		require.NoError(t, err)
+
+		var kvs []types.Value
+		for _, r := range []struct {
+			tv   row.TaggedValues
+			card uint64
+		}{
+			{tv: row.TaggedValues{keylessC0Tag: types.Int(1)}, card: 2},
+			{tv: row.TaggedValues{keylessC0Tag: types.Int(2)}, card: 1},
+			{tv: row.TaggedValues{keylessC0Tag: types.Int(2), keylessC1Tag: types.Int(4)}, card: 1},
+		} {
+			kr, err := row.NewKeylessRow(vrw.Format(), sch, r.tv, r.card)
+			require.NoError(t, err)
+			k, err := kr.NomsMapKey(sch).Value(ctx)
+			require.NoError(t, err)
+			v, err := kr.NomsMapValue(sch).Value(ctx)
+			require.NoError(t, err)
+			kvs = append(kvs, k, v)
+		}
+
+		rowData, err := types.NewMap(ctx, vrw, kvs...)
+		require.NoError(t, err)
+		emptyMap, err := types.NewMap(ctx, vrw)
+		require.NoError(t, err)
+		schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+		require.NoError(t, err)
+		tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData, emptyMap)
+		require.NoError(t, err)
+
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		sc := NewStatsCollector(rdr)
+		readAll(t, sc)
+
+		stats, err := sc.Stats()
+		require.NoError(t, err)
+		assert.Equal(t, ColumnStats{Min: types.Int(1), Max: types.Int(2), NullCount: 0, DistinctCount: 2}, stats[keylessC0Tag])
+		assert.Equal(t, ColumnStats{Min: types.Int(4), Max: types.Int(4), NullCount: 3, DistinctCount: 1}, stats[keylessC1Tag])
+	})
+
+	t.Run("distinct estimate", func(t *testing.T) {
+		const numRows = 20000
+
+		rdr, err := NewTableReader(ctx, newKeyedTestTable(t, numRows))
+		require.NoError(t, err)
+		sc := NewStatsCollector(rdr)
+		readAll(t, sc)
+
+		stats, err := sc.Stats()
+		require.NoError(t, err)
+		assert.InEpsilon(t, numRows, stats[1].DistinctCount, 0.05)
+	})
+}
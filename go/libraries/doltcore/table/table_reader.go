@@ -16,16 +16,23 @@ package table
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
+// ErrProjectionUnsupported is returned when creating a projected reader for a table with a primary key.
+var ErrProjectionUnsupported = errors.New("column projection is only supported for keyless tables")
+
 // TableReader is an interface for reading rows from a table
 type TableReader interface {
 	// GetSchema gets the schema of the rows that this reader will return
@@ -58,47 +65,112 @@ type SqlTableReader interface {
 }
 
 // NewTableReader creates a SqlTableReader from |tbl| starting from the first record.
-func NewTableReader(ctx context.Context, tbl *doltdb.Table) (SqlTableReader, error) {
+func NewTableReader(ctx context.Context, tbl *doltdb.Table, opts ...ReaderOption) (SqlTableReader, error) {
 	sch, err := tbl.GetSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var rdr SqlTableReader
 	if schema.IsKeyless(sch) {
-		return newKeylessTableReader(ctx, tbl, sch, false)
+		rdr, err = newKeylessTableReader(ctx, tbl, sch, false)
+	} else {
+		rdr, err = newPkTableReader(ctx, tbl, sch, false)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return newPkTableReader(ctx, tbl, sch, false)
+
+	return applyReaderOptions(rdr, opts)
 }
 
 // NewBufferedTableReader creates a buffered SqlTableReader from |tbl| starting from the first record.
-func NewBufferedTableReader(ctx context.Context, tbl *doltdb.Table) (SqlTableReader, error) {
+func NewBufferedTableReader(ctx context.Context, tbl *doltdb.Table, opts ...ReaderOption) (SqlTableReader, error) {
 	sch, err := tbl.GetSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var rdr SqlTableReader
 	if schema.IsKeyless(sch) {
-		return newKeylessTableReader(ctx, tbl, sch, true)
+		rdr, err = newKeylessTableReader(ctx, tbl, sch, true)
+	} else {
+		rdr, err = newPkTableReader(ctx, tbl, sch, true)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return newPkTableReader(ctx, tbl, sch, true)
+
+	return applyReaderOptions(rdr, opts)
+}
+
+// NewReverseTableReader creates a SqlTableReader from |tbl| that reads records in descending key order, starting
+// from the last record.
+func NewReverseTableReader(ctx context.Context, tbl *doltdb.Table, opts ...ReaderOption) (SqlTableReader, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rdr SqlTableReader
+	if schema.IsKeyless(sch) {
+		rdr, err = newKeylessTableReaderReverse(ctx, tbl, sch)
+	} else {
+		rdr, err = newPkTableReaderReverse(ctx, tbl, sch)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return applyReaderOptions(rdr, opts)
+}
+
+// NewTableReaderWithProjection creates a buffered SqlTableReader from |tbl| that returns rows with only the columns
+// whose tags are in |tags|, in the order given. The reader's schema contains just the projected columns. Projection
+// is currently only supported for keyless tables.
+func NewTableReaderWithProjection(ctx context.Context, tbl *doltdb.Table, tags []uint64, opts ...ReaderOption) (SqlTableReader, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !schema.IsKeyless(sch) {
+		return nil, ErrProjectionUnsupported
+	}
+
+	rdr, err := newKeylessTableReaderWithProjection(ctx, tbl, sch, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyReaderOptions(rdr, opts)
 }
 
 // NewBufferedTableReaderForPartition creates a SqlTableReader that reads the rows of |tbl| with indexes
-// in the half-open interval [start, end).
-func NewBufferedTableReaderForPartition(ctx context.Context, tbl *doltdb.Table, start, end uint64) (SqlTableReader, error) {
+// in the half-open interval [start, end). The rows of keyless tables are indexed by logical row, so that each copy
+// of a physical row has its own index.
+func NewBufferedTableReaderForPartition(ctx context.Context, tbl *doltdb.Table, start, end uint64, opts ...ReaderOption) (SqlTableReader, error) {
 	sch, err := tbl.GetSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var rdr SqlTableReader
 	if schema.IsKeyless(sch) {
-		return newKeylessTableReaderForPartition(ctx, tbl, sch, start, end)
+		rdr, err = newKeylessTableReaderForPartition(ctx, tbl, sch, start, end)
+	} else {
+		rdr, err = newPkTableReaderForPartition(ctx, tbl, sch, start, end)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return newPkTableReaderForPartition(ctx, tbl, sch, start, end)
+
+	return applyReaderOptions(rdr, opts)
 }
 
 // NewTableReaderForRanges creates a SqlTableReader that reads the rows of |tbl| corresponding to the
-// the noms.ReadRandes in |ranges|.
+// the noms.ReadRandes in |ranges|. Ranges are over primary key values, so ErrNoPrimaryKey is returned for keyless
+// tables.
 func NewTableReaderForRanges(ctx context.Context, tbl *doltdb.Table, ranges ...*noms.ReadRange) (SqlTableReader, error) {
 	sch, err := tbl.GetSchema(ctx)
 	if err != nil {
@@ -106,21 +178,161 @@ func NewTableReaderForRanges(ctx context.Context, tbl *doltdb.Table, ranges ...*
 	}
 
 	if schema.IsKeyless(sch) {
-		return newKeylessTableReaderForRanges(ctx, tbl, sch, ranges...)
+		return nil, ErrNoPrimaryKey
 	}
 	return newPkTableReaderForRanges(ctx, tbl, sch, ranges...)
 }
 
+// NewTableReaderForKeyPrefix creates a SqlTableReader that reads the rows of |tbl| whose leading primary key columns
+// equal the values in |prefix|, in key order. The reader seeks directly to the first matching row and stops at the
+// first row past the prefix. |sch| is the schema of |tbl|, which must have a primary key with at least
+// len(|prefix|) columns. An empty prefix reads the whole table.
+func NewTableReaderForKeyPrefix(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, prefix []types.Value) (SqlTableReader, error) {
+	if schema.IsKeyless(sch) {
+		return nil, ErrNoPrimaryKey
+	}
+
+	pkCols := sch.GetPKCols()
+	if len(prefix) > pkCols.Size() {
+		return nil, fmt.Errorf("key prefix has %d values but the primary key has %d columns", len(prefix), pkCols.Size())
+	}
+
+	vals := make([]types.Value, 0, 2*len(prefix))
+	for i, v := range prefix {
+		vals = append(vals, types.Uint(pkCols.GetAtIndex(i).Tag), v)
+	}
+
+	start, err := types.NewTuple(tbl.Format(), vals...)
+	if err != nil {
+		return nil, err
+	}
+
+	rng := noms.NewRangeStartingAt(start, func(tuple types.Tuple) (bool, error) {
+		return tuple.StartsWith(start), nil
+	})
+
+	return newPkTableReaderForRanges(ctx, tbl, sch, rng)
+}
+
 // NewTableReaderFrom creates a SqlTableReader that reads the rows of |tbl| beginning at the record
 // whose types.Map key is >= |val|.
-func NewTableReaderFrom(ctx context.Context, tbl *doltdb.Table, val types.Value) (SqlTableReader, error) {
+func NewTableReaderFrom(ctx context.Context, tbl *doltdb.Table, val types.Value, opts ...ReaderOption) (SqlTableReader, error) {
 	sch, err := tbl.GetSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var rdr SqlTableReader
 	if schema.IsKeyless(sch) {
-		return newKeylessTableReaderFrom(ctx, tbl, sch, val)
+		rdr, err = newKeylessTableReaderFrom(ctx, tbl, sch, val)
+	} else {
+		rdr, err = newPkTableReaderFrom(ctx, tbl, sch, val)
 	}
-	return newPkTableReaderFrom(ctx, tbl, sch, val)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyReaderOptions(rdr, opts)
+}
+
+// NewTableReaderAtCommit creates a SqlTableReader over the table named |tableName| in the root value of the commit
+// |cm|. Rows are read using |sch|, or the table's schema at that commit if |sch| is nil. An error wrapping
+// doltdb.ErrTableNotFound is returned if the table did not exist at that commit.
+func NewTableReaderAtCommit(ctx context.Context, cm *doltdb.Commit, tableName string, sch schema.Schema, opts ...ReaderOption) (SqlTableReader, error) {
+	root, err := cm.GetRootValue()
+	if err != nil {
+		return nil, err
+	}
+
+	rdr, ok, err := newTableReaderAtRoot(ctx, root, tableName, sch, opts)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: '%s' does not exist at commit %s", doltdb.ErrTableNotFound, tableName, h.String())
+	}
+
+	return rdr, nil
+}
+
+// NewTableReaderAtTag creates a buffered SqlTableReader over the table named |tableName| as of the commit pointed to
+// by the tag |tagName|, using the table's schema at that commit. Keyless tables are read with a keyless reader. If the
+// tag does not exist the error is doltdb.ErrTagNotFound, and if the table does not exist at the tag's commit the
+// error wraps doltdb.ErrTableNotFound.
+func NewTableReaderAtTag(ctx context.Context, ddb *doltdb.DoltDB, tagName string, tableName string, opts ...ReaderOption) (SqlTableReader, error) {
+	tag, err := ddb.ResolveTag(ctx, ref.NewTagRef(tagName))
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := tag.Commit.GetRootValue()
+	if err != nil {
+		return nil, err
+	}
+
+	rdr, ok, err := newTableReaderAtRoot(ctx, root, tableName, nil, opts)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("%w: '%s' does not exist at tag %s", doltdb.ErrTableNotFound, tableName, tagName)
+	}
+
+	return rdr, nil
+}
+
+// NewTableReaderAtRootHash creates a buffered SqlTableReader over the table named |tableName| in the root value with
+// the hash |rootHash| in |ddb|, such as a hash returned by doltdb.DoltDB.WriteRootValue. It needs no environment or
+// commit, so it can be used by callers that only have a DoltDB. Rows are read using |sch|, or the table's schema in
+// that root if |sch| is nil. An error is returned if there is no root value with that hash, and an error wrapping
+// doltdb.ErrTableNotFound is returned if the table does not exist in it.
+func NewTableReaderAtRootHash(ctx context.Context, ddb *doltdb.DoltDB, rootHash hash.Hash, tableName string, sch schema.Schema, opts ...ReaderOption) (SqlTableReader, error) {
+	root, err := ddb.ReadRootValue(ctx, rootHash)
+	if err != nil {
+		return nil, err
+	}
+
+	rdr, ok, err := newTableReaderAtRoot(ctx, root, tableName, sch, opts)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("%w: '%s' does not exist at root %s", doltdb.ErrTableNotFound, tableName, rootHash.String())
+	}
+
+	return rdr, nil
+}
+
+// newTableReaderAtRoot creates a buffered reader over the table named |tableName| in |root|. If |sch| is nil the
+// table's schema is used. Returns false if the table does not exist in |root|.
+func newTableReaderAtRoot(ctx context.Context, root *doltdb.RootValue, tableName string, sch schema.Schema, opts []ReaderOption) (SqlTableReader, bool, error) {
+	tbl, ok, err := root.GetTable(ctx, tableName)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	if sch == nil {
+		sch, err = tbl.GetSchema(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	var rdr SqlTableReader
+	if schema.IsKeyless(sch) {
+		rdr, err = newKeylessTableReader(ctx, tbl, sch, true)
+	} else {
+		rdr, err = newPkTableReader(ctx, tbl, sch, true)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	rdr, err = applyReaderOptions(rdr, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return rdr, true, nil
 }
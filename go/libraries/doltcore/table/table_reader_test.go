@@ -0,0 +1,387 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestNewTableReaderAtCommit(t *testing.T) {
+	ctx := context.Background()
+
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_Default, doltdb.InMemDoltDB)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	commitRoot := func(root *doltdb.RootValue, msg string) *doltdb.Commit {
+		valHash, err := ddb.WriteRootValue(ctx, root)
+		require.NoError(t, err)
+		meta, err := doltdb.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", msg)
+		require.NoError(t, err)
+		cm, err := ddb.Commit(ctx, valHash, ref.NewBranchRef("master"), meta)
+		require.NoError(t, err)
+		return cm
+	}
+
+	cs, err := doltdb.NewCommitSpec("master")
+	require.NoError(t, err)
+	initial, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	root, err := initial.GetRootValue()
+	require.NoError(t, err)
+
+	tbl := newKeyedTestTableWithVRW(t, ddb.ValueReadWriter(), 10)
+	root, err = root.PutTable(ctx, "t", tbl)
+	require.NoError(t, err)
+	created := commitRoot(root, "create t")
+
+	root, err = root.RemoveTables(ctx, "t")
+	require.NoError(t, err)
+	dropped := commitRoot(root, "drop t")
+
+	t.Run("table exists at commit", func(t *testing.T) {
+		rdr, err := NewTableReaderAtCommit(ctx, created, "t", nil)
+		require.NoError(t, err)
+
+		rows := readAllSqlRows(t, rdr)
+		require.Len(t, rows, 10)
+		for i, r := range rows {
+			assert.Equal(t, int64(i), r[0])
+			assert.Equal(t, int64(i*10), r[1])
+		}
+	})
+
+	t.Run("with schema", func(t *testing.T) {
+		sch, err := tbl.GetSchema(ctx)
+		require.NoError(t, err)
+
+		rdr, err := NewTableReaderAtCommit(ctx, created, "t", sch)
+		require.NoError(t, err)
+		assert.Len(t, readAllSqlRows(t, rdr), 10)
+	})
+
+	t.Run("table dropped in a later commit", func(t *testing.T) {
+		_, err := NewTableReaderAtCommit(ctx, dropped, "t", nil)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, doltdb.ErrTableNotFound))
+	})
+
+	t.Run("table did not exist yet", func(t *testing.T) {
+		_, err := NewTableReaderAtCommit(ctx, initial, "t", nil)
+		assert.True(t, errors.Is(err, doltdb.ErrTableNotFound))
+	})
+}
+
+func TestNewTableReaderAtRootHash(t *testing.T) {
+	ctx := context.Background()
+
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_Default, doltdb.InMemDoltDB)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	cs, err := doltdb.NewCommitSpec("master")
+	require.NoError(t, err)
+	initial, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	root, err := initial.GetRootValue()
+	require.NoError(t, err)
+
+	// the root value is written without being committed, so it is only reachable by its hash
+	tbl := newKeyedTestTableWithVRW(t, ddb.ValueReadWriter(), 5)
+	root, err = root.PutTable(ctx, "t", tbl)
+	require.NoError(t, err)
+	rootHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+
+	t.Run("table schema", func(t *testing.T) {
+		rdr, err := NewTableReaderAtRootHash(ctx, ddb, rootHash, "t", nil)
+		require.NoError(t, err)
+		defer rdr.Close(ctx)
+
+		rows := readAllSqlRows(t, rdr)
+		require.Len(t, rows, 5)
+		for i, r := range rows {
+			assert.Equal(t, int64(i), r[0])
+			assert.Equal(t, int64(i*10), r[1])
+		}
+	})
+
+	t.Run("with schema and options", func(t *testing.T) {
+		sch, err := tbl.GetSchema(ctx)
+		require.NoError(t, err)
+
+		rdr, err := NewTableReaderAtRootHash(ctx, ddb, rootHash, "t", sch, WithOrderBy(1))
+		require.NoError(t, err)
+		defer rdr.Close(ctx)
+		assert.Len(t, readAllSqlRows(t, rdr), 5)
+	})
+
+	t.Run("table does not exist", func(t *testing.T) {
+		_, err := NewTableReaderAtRootHash(ctx, ddb, rootHash, "not_a_table", nil)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, doltdb.ErrTableNotFound))
+	})
+
+	t.Run("no root value at hash", func(t *testing.T) {
+		_, err := NewTableReaderAtRootHash(ctx, ddb, hash.Of([]byte("not a root")), "t", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewTableReaderAtTag(t *testing.T) {
+	schema.FeatureFlagKeylessSchema = true
+	defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+	ctx := context.Background()
+
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_Default, doltdb.InMemDoltDB)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	commitRoot := func(root *doltdb.RootValue, msg string) *doltdb.Commit {
+		valHash, err := ddb.WriteRootValue(ctx, root)
+		require.NoError(t, err)
+		meta, err := doltdb.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", msg)
+		require.NoError(t, err)
+		cm, err := ddb.Commit(ctx, valHash, ref.NewBranchRef("master"), meta)
+		require.NoError(t, err)
+		return cm
+	}
+	tagCommit := func(tagName string, cm *doltdb.Commit) {
+		meta := doltdb.NewTagMeta("Bill Billerson", "bigbillieb@fake.horse", tagName)
+		require.NoError(t, ddb.NewTagAtCommit(ctx, ref.NewTagRef(tagName), cm, meta))
+	}
+
+	cs, err := doltdb.NewCommitSpec("master")
+	require.NoError(t, err)
+	initial, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	tagCommit("empty", initial)
+	root, err := initial.GetRootValue()
+	require.NoError(t, err)
+
+	sch := mustKeylessSchema(t)
+	tbl := newKeylessTestTableWithVRW(t, ddb.ValueReadWriter(), sch,
+		keylessTestRow{c0: 0, c1: 0, card: 1},
+		keylessTestRow{c0: 1, c1: 1, card: 2},
+	)
+	root, err = root.PutTable(ctx, "t", tbl)
+	require.NoError(t, err)
+	tagCommit("v1", commitRoot(root, "create t"))
+
+	// add a column and replace the rows after the tag was created
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("c0", keylessC0Tag, types.IntKind, false),
+		schema.NewColumn("c1", keylessC1Tag, types.IntKind, false),
+		schema.NewColumn("c2", 2, types.IntKind, false),
+	)
+	require.NoError(t, err)
+	newSch, err := schema.SchemaFromCols(coll)
+	require.NoError(t, err)
+	tbl = newKeylessTestTableWithVRW(t, ddb.ValueReadWriter(), newSch, keylessTestRow{c0: 5, c1: 5, card: 5})
+	root, err = root.PutTable(ctx, "t", tbl)
+	require.NoError(t, err)
+	commitRoot(root, "add c2 to t")
+
+	t.Run("table at tag", func(t *testing.T) {
+		rdr, err := NewTableReaderAtTag(ctx, ddb, "v1", "t")
+		require.NoError(t, err)
+		assert.Equal(t, 2, rdr.GetSchema().GetAllCols().Size())
+		assert.Equal(t, map[int64]uint64{0: 1, 1: 2}, readKeylessMultiset(t, rdr))
+
+		rdr, err = NewTableReaderAtTag(ctx, ddb, "v1", "t")
+		require.NoError(t, err)
+		for _, r := range readAllSqlRows(t, rdr) {
+			assert.Len(t, r, 2)
+		}
+	})
+
+	t.Run("tag not found", func(t *testing.T) {
+		_, err := NewTableReaderAtTag(ctx, ddb, "v2", "t")
+		assert.True(t, errors.Is(err, doltdb.ErrTagNotFound))
+		assert.False(t, errors.Is(err, doltdb.ErrTableNotFound))
+	})
+
+	t.Run("table not found at tag", func(t *testing.T) {
+		_, err := NewTableReaderAtTag(ctx, ddb, "empty", "t")
+		assert.True(t, errors.Is(err, doltdb.ErrTableNotFound))
+		assert.False(t, errors.Is(err, doltdb.ErrTagNotFound))
+	})
+}
+
+// readCountingTable opens tables whose row data is read from a chunk store which counts its reads.
+type readCountingTable struct {
+	cs       *chunks.TestStoreView
+	sch      schema.Schema
+	rowsHash hash.Hash
+}
+
+// newReadCountingTable writes the rows created by |mkTable| to a chunk store which counts its reads.
+func newReadCountingTable(t *testing.T, mkTable func(vrw types.ValueReadWriter) *doltdb.Table) readCountingTable {
+	ctx := context.Background()
+	cs := (&chunks.TestStorage{}).NewView()
+	vs := types.NewValueStore(cs)
+
+	tbl := mkTable(vs)
+	sch, err := tbl.GetSchema(ctx)
+	require.NoError(t, err)
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+
+	ref, err := vs.WriteValue(ctx, rowData)
+	require.NoError(t, err)
+	last, err := vs.Root(ctx)
+	require.NoError(t, err)
+	ok, err := vs.Commit(ctx, ref.TargetHash(), last)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	return readCountingTable{cs: cs, sch: sch, rowsHash: ref.TargetHash()}
+}
+
+// readsForFirstRow opens the table with an empty value cache, and returns the number of chunks read from the store
+// while creating a reader with |newReader| and reading its first row.
+func (rct readCountingTable) readsForFirstRow(t *testing.T, newReader func(context.Context, *doltdb.Table, ...ReaderOption) (SqlTableReader, error)) int {
+	ctx := context.Background()
+	vs := types.NewValueStore(rct.cs)
+
+	rowData, err := vs.ReadValue(ctx, rct.rowsHash)
+	require.NoError(t, err)
+	emptyMap, err := types.NewMap(ctx, vs)
+	require.NoError(t, err)
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vs, rct.sch)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vs, schVal, rowData.(types.Map), emptyMap)
+	require.NoError(t, err)
+
+	before := rct.cs.Reads()
+
+	rdr, err := newReader(ctx, tbl)
+	require.NoError(t, err)
+	_, err = rdr.ReadRow(ctx)
+	require.NoError(t, err)
+
+	return rct.cs.Reads() - before
+}
+
+func TestBufferedTableReaders(t *testing.T) {
+	// enough rows for the row data to span many chunks, which buffered readers read ahead of the first row
+	const numRows = 5000
+
+	t.Run("keyed", func(t *testing.T) {
+		rct := newReadCountingTable(t, func(vrw types.ValueReadWriter) *doltdb.Table {
+			return newKeyedTestTableWithVRW(t, vrw, numRows)
+		})
+
+		unbuffered := rct.readsForFirstRow(t, NewTableReader)
+		buffered := rct.readsForFirstRow(t, NewBufferedTableReader)
+		assert.LessOrEqual(t, unbuffered, 2)
+		assert.Greater(t, buffered, unbuffered)
+	})
+
+	t.Run("keyless", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		rct := newReadCountingTable(t, func(vrw types.ValueReadWriter) *doltdb.Table {
+			var rows []keylessTestRow
+			for i := int64(0); i < numRows; i++ {
+				rows = append(rows, keylessTestRow{c0: i, c1: i, card: 2})
+			}
+			return newKeylessTestTableWithVRW(t, vrw, mustKeylessSchema(t), rows...)
+		})
+
+		unbuffered := rct.readsForFirstRow(t, NewTableReader)
+		buffered := rct.readsForFirstRow(t, NewBufferedTableReader)
+		assert.LessOrEqual(t, unbuffered, 2)
+		assert.Greater(t, buffered, unbuffered)
+	})
+}
+
+func TestNewTableReaderForKeyPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	vrw, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_Default, nil, nil)
+	require.NoError(t, err)
+
+	coll, err := schema.NewColCollection(
+		schema.NewColumn("a", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("b", 1, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("v", 2, types.IntKind, false),
+	)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(coll)
+
+	emptyMap, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	me := emptyMap.Edit()
+	for a := 0; a < 5; a++ {
+		for b := 0; b < 5; b++ {
+			r, err := row.New(vrw.Format(), sch, row.TaggedValues{0: types.Int(a), 1: types.Int(b), 2: types.Int(a*10 + b)})
+			require.NoError(t, err)
+			me.Set(r.NomsMapKey(sch), r.NomsMapValue(sch))
+		}
+	}
+	rowData, err := me.Map(ctx)
+	require.NoError(t, err)
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData, emptyMap)
+	require.NoError(t, err)
+
+	readVals := func(t *testing.T, prefix ...types.Value) []int64 {
+		rdr, err := NewTableReaderForKeyPrefix(ctx, tbl, sch, prefix)
+		require.NoError(t, err)
+
+		var vals []int64
+		for _, r := range readAllSqlRows(t, rdr) {
+			vals = append(vals, r[2].(int64))
+		}
+		return vals
+	}
+
+	assert.Equal(t, []int64{30, 31, 32, 33, 34}, readVals(t, types.Int(3)))
+	assert.Equal(t, []int64{0, 1, 2, 3, 4}, readVals(t, types.Int(0)))
+	assert.Equal(t, []int64{42}, readVals(t, types.Int(4), types.Int(2)))
+	assert.Empty(t, readVals(t, types.Int(7)))
+	assert.Len(t, readVals(t), 25)
+
+	_, err = NewTableReaderForKeyPrefix(ctx, tbl, sch, []types.Value{types.Int(1), types.Int(1), types.Int(1)})
+	assert.Error(t, err)
+
+	t.Run("keyless", func(t *testing.T) {
+		schema.FeatureFlagKeylessSchema = true
+		defer func() { schema.FeatureFlagKeylessSchema = false }()
+
+		keylessSch := mustKeylessSchema(t)
+		_, err := NewTableReaderForKeyPrefix(ctx, newKeylessTestTable(t, keylessSch), keylessSch, nil)
+		assert.Equal(t, ErrNoPrimaryKey, err)
+	})
+}
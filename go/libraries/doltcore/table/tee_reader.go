@@ -0,0 +1,124 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// TeeReader is one of a pair of SqlTableReaders which both return every row of a single source reader, so that a
+// table can be scanned once for two consumers. Whichever reader of the pair is ahead reads rows from the source, and
+// buffers them until the other reader catches up. The buffer is unbounded, so a consumer that stops reading, or reads
+// much more slowly than the other, causes rows to accumulate in memory until it reads them or closes its reader.
+// Errors from the source, including io.EOF, are returned to each reader after it has read every row before the
+// error. The two readers may be used from different goroutines.
+type TeeReader struct {
+	src *teeSource
+	idx int
+}
+
+var _ SqlTableReader = &TeeReader{}
+
+// teeSource holds the state shared by a pair of TeeReaders.
+type teeSource struct {
+	mu   sync.Mutex
+	rdr  SqlTableReader
+	conv *row.SqlRowConverter
+
+	// bufs holds the rows read from the source by one reader that the other reader has not read yet.
+	bufs   [2][]row.Row
+	closed [2]bool
+	err    error
+}
+
+// NewTeeReader creates a pair of TeeReaders which each return every row of |rdr|. The source is closed when both
+// readers have been closed.
+func NewTeeReader(rdr SqlTableReader) (*TeeReader, *TeeReader) {
+	src := &teeSource{rdr: rdr, conv: row.NewSqlRowConverter(rdr.GetSchema())}
+	return &TeeReader{src: src, idx: 0}, &TeeReader{src: src, idx: 1}
+}
+
+// GetSchema implements the TableReader interface.
+func (rd *TeeReader) GetSchema() schema.Schema {
+	return rd.src.rdr.GetSchema()
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *TeeReader) ReadRow(ctx context.Context) (row.Row, error) {
+	src := rd.src
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if len(src.bufs[rd.idx]) > 0 {
+		r := src.bufs[rd.idx][0]
+		src.bufs[rd.idx][0] = nil
+		src.bufs[rd.idx] = src.bufs[rd.idx][1:]
+		return r, nil
+	}
+
+	if src.err != nil {
+		return nil, src.err
+	}
+
+	r, err := src.rdr.ReadRow(ctx)
+	if err != nil {
+		src.err = err
+		return nil, err
+	}
+
+	other := 1 - rd.idx
+	if !src.closed[other] {
+		src.bufs[other] = append(src.bufs[other], r)
+	}
+
+	return r, nil
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *TeeReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.src.conv.Convert(r)
+}
+
+// Close releases the rows buffered for this reader. The source is closed, if it can be closed, once both readers of
+// the pair have been closed.
+func (rd *TeeReader) Close(ctx context.Context) error {
+	src := rd.src
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if src.closed[rd.idx] {
+		return nil
+	}
+
+	src.closed[rd.idx] = true
+	src.bufs[rd.idx] = nil
+
+	if src.closed[1-rd.idx] {
+		return closeReader(ctx, src.rdr)
+	}
+
+	return nil
+}
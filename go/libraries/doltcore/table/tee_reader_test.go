@@ -0,0 +1,132 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeeReader(t *testing.T) {
+	ctx := context.Background()
+	tbl := newKeyedTestTable(t, 100)
+
+	expectedRdr, err := NewTableReader(ctx, tbl)
+	require.NoError(t, err)
+	expected := readAllSqlRows(t, expectedRdr)
+
+	newTee := func(t *testing.T) (*TeeReader, *TeeReader) {
+		rdr, err := NewTableReader(ctx, tbl)
+		require.NoError(t, err)
+		return NewTeeReader(rdr)
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		a, b := newTee(t)
+		assert.Equal(t, expected, readAllSqlRows(t, a))
+		assert.Equal(t, expected, readAllSqlRows(t, b))
+	})
+
+	t.Run("interleaved", func(t *testing.T) {
+		a, b := newTee(t)
+
+		var aRows, bRows []sql.Row
+		for i := 0; ; i++ {
+			// a reads three rows for each row b reads until it is done
+			rdr, rows := b, &bRows
+			if i%4 != 0 && len(aRows) < len(expected) {
+				rdr, rows = a, &aRows
+			}
+
+			r, err := rdr.ReadSqlRow(ctx)
+			if err == io.EOF {
+				if rdr == b {
+					break
+				}
+				continue
+			}
+			require.NoError(t, err)
+			*rows = append(*rows, r)
+		}
+
+		assert.Equal(t, expected, aRows)
+		assert.Equal(t, expected, bRows)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		a, b := newTee(t)
+
+		var wg sync.WaitGroup
+		results := make([][]sql.Row, 2)
+		for i, rdr := range []*TeeReader{a, b} {
+			wg.Add(1)
+			go func(i int, rdr *TeeReader) {
+				defer wg.Done()
+				for {
+					r, err := rdr.ReadSqlRow(ctx)
+					if err != nil {
+						assert.Equal(t, io.EOF, err)
+						return
+					}
+					results[i] = append(results[i], r)
+				}
+			}(i, rdr)
+		}
+		wg.Wait()
+
+		assert.Equal(t, expected, results[0])
+		assert.Equal(t, expected, results[1])
+	})
+
+	t.Run("closed reader stops buffering", func(t *testing.T) {
+		a, b := newTee(t)
+		require.NoError(t, b.Close(ctx))
+
+		assert.Equal(t, expected, readAllSqlRows(t, a))
+		assert.Empty(t, a.src.bufs[1])
+	})
+
+	t.Run("source closed after both readers", func(t *testing.T) {
+		src := &closeTrackingReader{InMemTableReader: NewInMemTableReader(NewInMemTableWithData(rowSch, rows))}
+		a, b := NewTeeReader(src)
+
+		require.NoError(t, a.Close(ctx))
+		assert.False(t, src.closed)
+		require.NoError(t, b.Close(ctx))
+		assert.True(t, src.closed)
+	})
+
+	t.Run("source errors", func(t *testing.T) {
+		readErr := errors.New("read failed")
+		src := &closeTrackingReader{InMemTableReader: NewInMemTableReader(NewInMemTableWithData(rowSch, rows)), readErr: readErr}
+		a, b := NewTeeReader(src)
+
+		for _, rdr := range []*TeeReader{a, b} {
+			for i := 0; i < len(rows); i++ {
+				_, err := rdr.ReadRow(ctx)
+				require.NoError(t, err)
+			}
+			_, err := rdr.ReadRow(ctx)
+			assert.Equal(t, readErr, err)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+)
+
+// TypeConstraint is the Constraint of a RowViolation for a value which does not fit the type of its column.
+const TypeConstraint = "type"
+
+// RowViolation is an error describing a row which does not satisfy the schema of the reader it was read from.
+type RowViolation struct {
+	// Position is the zero-based position of the row among the rows read from the underlying reader. Each copy of a
+	// keyless row has its own position.
+	Position uint64
+	Row      row.Row
+	Column   string
+	// Constraint is the type of the violated column constraint, such as schema.NotNullConstraintType, or
+	// TypeConstraint if the value does not fit the column's type.
+	Constraint string
+	Details    string
+}
+
+// Error implements the error interface.
+func (v *RowViolation) Error() string {
+	return fmt.Sprintf("row %d: column '%s' violates %s constraint: %s", v.Position, v.Column, v.Constraint, v.Details)
+}
+
+// ValidatingReader is a SqlTableReader which checks each row read from another reader against the constraints and
+// column types of its schema. By default the first invalid row is returned as a *RowViolation error. If violations
+// are being collected, invalid rows are skipped instead and can be retrieved with Violations.
+type ValidatingReader struct {
+	SqlTableReader
+	conv       *row.SqlRowConverter
+	collect    bool
+	position   uint64
+	violations []*RowViolation
+}
+
+var _ SqlTableReader = &ValidatingReader{}
+
+// NewValidatingReader creates a ValidatingReader for the rows of |rdr|. If |collect| is true, invalid rows are skipped
+// and recorded rather than ending the read with an error.
+func NewValidatingReader(rdr SqlTableReader, collect bool) *ValidatingReader {
+	return &ValidatingReader{
+		SqlTableReader: rdr,
+		conv:           row.NewSqlRowConverter(rdr.GetSchema()),
+		collect:        collect,
+	}
+}
+
+// ReadRow implements the TableReader interface.
+func (rd *ValidatingReader) ReadRow(ctx context.Context) (row.Row, error) {
+	for {
+		r, err := rd.SqlTableReader.ReadRow(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		violation, err := rd.validate(r)
+		if err != nil {
+			return nil, err
+		} else if violation == nil {
+			return r, nil
+		} else if !rd.collect {
+			return nil, violation
+		}
+
+		rd.violations = append(rd.violations, violation)
+	}
+}
+
+// ReadSqlRow implements the SqlTableReader interface.
+func (rd *ValidatingReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := rd.ReadRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.conv.Convert(r)
+}
+
+// Violations returns the violations found in the rows read so far. It is always empty unless violations are being
+// collected.
+func (rd *ValidatingReader) Violations() []*RowViolation {
+	return rd.violations
+}
+
+// Close closes the underlying reader if it can be closed.
+func (rd *ValidatingReader) Close(ctx context.Context) error {
+	return closeReader(ctx, rd.SqlTableReader)
+}
+
+// validate returns a RowViolation for the first column of |r| which does not satisfy the schema, or nil if the row
+// is valid.
+func (rd *ValidatingReader) validate(r row.Row) (*RowViolation, error) {
+	pos := rd.position
+	rd.position++
+
+	col, cnst, err := row.GetInvalidConstraint(r, rd.GetSchema())
+	if col == nil {
+		return nil, err
+	}
+
+	violation := &RowViolation{Position: pos, Row: r, Column: col.Name}
+	if cnst != nil {
+		violation.Constraint = cnst.GetConstraintType()
+		violation.Details = cnst.String()
+	} else if err != nil {
+		violation.Constraint = TypeConstraint
+		violation.Details = err.Error()
+	} else {
+		val, _ := r.GetColVal(col.Tag)
+		violation.Constraint = TypeConstraint
+		violation.Details = fmt.Sprintf("value of kind %s is not valid for %s", val.Kind().String(), col.TypeInfo.String())
+	}
+
+	return violation, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestValidatingReader(t *testing.T) {
+	ctx := context.Background()
+
+	varchar5, err := typeinfo.FromSqlType(sql.MustCreateStringWithDefaults(sqltypes.VarChar, 5))
+	require.NoError(t, err)
+	pkCol, err := schema.NewColumnWithTypeInfo("pk", 0, typeinfo.Int64Type, true, "", false, "", schema.NotNullConstraint{})
+	require.NoError(t, err)
+	nameCol, err := schema.NewColumnWithTypeInfo("name", 1, varchar5, false, "", false, "", schema.NotNullConstraint{})
+	require.NoError(t, err)
+	colColl, err := schema.NewColCollection(pkCol, nameCol)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(colColl)
+
+	newRow := func(pk int64, name types.Value) row.Row {
+		vals := row.TaggedValues{0: types.Int(pk)}
+		if name != nil {
+			vals[1] = name
+		}
+		r, err := row.New(types.Format_Default, sch, vals)
+		require.NoError(t, err)
+		return r
+	}
+
+	rows := []row.Row{
+		newRow(0, types.String("a")),
+		newRow(1, nil),
+		newRow(2, types.String("bb")),
+		newRow(3, types.String("abcdefg")),
+		newRow(4, types.String("c")),
+	}
+	newReader := func(collect bool) *ValidatingReader {
+		return NewValidatingReader(NewInMemTableReader(NewInMemTableWithData(sch, rows)), collect)
+	}
+
+	t.Run("first violation", func(t *testing.T) {
+		rd := newReader(false)
+		r, err := rd.ReadRow(ctx)
+		require.NoError(t, err)
+		assert.True(t, row.AreEqual(rows[0], r, sch))
+
+		_, err = rd.ReadRow(ctx)
+		var violation *RowViolation
+		require.True(t, errors.As(err, &violation))
+		assert.Equal(t, uint64(1), violation.Position)
+		assert.Equal(t, "name", violation.Column)
+		assert.Equal(t, schema.NotNullConstraintType, violation.Constraint)
+		assert.True(t, row.AreEqual(rows[1], violation.Row, sch))
+		assert.Empty(t, rd.Violations())
+	})
+
+	t.Run("collect violations", func(t *testing.T) {
+		rd := newReader(true)
+		var pks []int64
+		for {
+			r, err := rd.ReadSqlRow(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			pks = append(pks, r[0].(int64))
+		}
+		require.NoError(t, rd.Close(ctx))
+		assert.Equal(t, []int64{0, 2, 4}, pks)
+
+		violations := rd.Violations()
+		require.Len(t, violations, 2)
+		assert.Equal(t, uint64(1), violations[0].Position)
+		assert.Equal(t, schema.NotNullConstraintType, violations[0].Constraint)
+		assert.Equal(t, uint64(3), violations[1].Position)
+		assert.Equal(t, "name", violations[1].Column)
+		assert.Equal(t, TypeConstraint, violations[1].Constraint)
+	})
+}
@@ -443,6 +443,39 @@ func (m Map) IterAll(ctx context.Context, cb mapIterAllCallback) error {
 	return nil
 }
 
+type mapIterValuesCallback func(value Value) error
+
+// IterValues calls |cb| with each value in the map, in key order. Keys are skipped over without being decoded.
+func (m Map) IterValues(ctx context.Context, cb mapIterValuesCallback) error {
+	cur, err := newCursorAtIndex(ctx, m.orderedSequence, 0)
+
+	if err != nil {
+		return err
+	}
+
+	for cur.valid() {
+		v, err := getMapValue(cur)
+
+		if err != nil {
+			return err
+		}
+
+		err = cb(v)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = cur.advance(ctx)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m Map) IterFrom(ctx context.Context, start Value, cb mapIterCallback) error {
 	cur, err := newCursorAtValue(ctx, m.orderedSequence, start, false, false)
 
@@ -1311,6 +1311,41 @@ func TestMapIterAll(t *testing.T) {
 	doTest(getTestRefToValueOrderMap, 2)
 }
 
+func TestMapIterValues(t *testing.T) {
+	smallTestChunks()
+	defer normalProductionChunks()
+
+	assert := assert.New(t)
+
+	doTest := func(toTestMap toTestMapFunc, scale int) {
+		vrw := newTestValueStore()
+		tm := toTestMap(scale, vrw)
+		m := tm.toMap(vrw)
+		err := SortWithErroringLess(tm.entries)
+		assert.NoError(err)
+		idx := uint64(0)
+
+		err = m.IterValues(context.Background(), func(v Value) error {
+			assert.True(tm.entries.entries[idx].value.Equals(v))
+			idx++
+
+			return nil
+		})
+
+		assert.NoError(err)
+		assert.Equal(m.Len(), idx)
+	}
+
+	doTest(getTestNativeOrderMap, 16)
+	doTest(getTestRefValueOrderMap, 2)
+
+	err := mustMap(NewMap(context.Background(), newTestValueStore())).IterValues(context.Background(), func(v Value) error {
+		assert.Fail("empty map has no values")
+		return nil
+	})
+	assert.NoError(err)
+}
+
 func TestMapEquals(t *testing.T) {
 	assert := assert.New(t)
 
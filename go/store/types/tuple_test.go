@@ -285,3 +285,28 @@ func TestTupleStartsWith(t *testing.T) {
 		})
 	}
 }
+
+func TestTupleIteratorSkip(t *testing.T) {
+	values := []Value{String("aoeu"), Int(-1234), String("skipped"), Uint(1234)}
+	tpl, err := NewTuple(Format_Default, values...)
+	require.NoError(t, err)
+
+	itr, err := tpl.Iterator()
+	require.NoError(t, err)
+
+	require.NoError(t, itr.Skip())
+	pos, val, err := itr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), pos)
+	assert.Equal(t, Int(-1234), val)
+
+	require.NoError(t, itr.Skip())
+	pos, val, err = itr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), pos)
+	assert.Equal(t, Uint(1234), val)
+
+	assert.False(t, itr.HasMore())
+	require.NoError(t, itr.Skip())
+	assert.Equal(t, uint64(4), itr.Pos())
+}